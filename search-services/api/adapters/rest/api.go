@@ -1,11 +1,13 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"yadro.com/course/api/core"
 )
@@ -158,64 +160,347 @@ func NewDropHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
 	}
 }
 
+type IndexStatusResponse struct {
+	Status     string     `json:"status"`
+	LastBuild  *time.Time `json:"last_build,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	Comics     int        `json:"comics"`
+	Vocabulary int        `json:"vocabulary"`
+	LastError  string     `json:"last_error,omitempty"`
+	Cache      CacheStats `json:"cache"`
+}
+
+type CacheStats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+	Size   int `json:"size"`
+}
+
+func NewIndexStatusHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := searcher.IndexStatus(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := IndexStatusResponse{
+			Status:     string(res.Status),
+			DurationMs: res.Duration.Milliseconds(),
+			Comics:     res.Comics,
+			Vocabulary: res.Vocabulary,
+			LastError:  res.LastError,
+			Cache:      CacheStats{Hits: res.Cache.Hits, Misses: res.Cache.Misses, Size: res.Cache.Size},
+		}
+		if !res.LastBuild.IsZero() {
+			response.LastBuild = &res.LastBuild
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error("cannot encode reply", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 type Comics struct {
-	Id  int    `json:"id"`
-	Url string `json:"url"`
+	Id    int     `json:"id"`
+	Url   string  `json:"url"`
+	Score float64 `json:"score"`
+	// Snippet is HTML: a piece of the alt text or the transcript
+	// with found words in <b>.
+	Snippet string `json:"snippet,omitempty"`
+	// Explain is set with explain=true.
+	Explain *Explanation `json:"explain,omitempty"`
+}
+
+// Explanation shows how the score of a comic was made up: the backend
+// that found it and the score every normalized query word added.
+// A word score is its BM25 score on its own times its weight in the
+// query, lower for fuzzy expansions and synonyms; the fts backend
+// does not split scores by words.
+type Explanation struct {
+	Backend string            `json:"backend"`
+	Terms   []TermExplanation `json:"terms"`
+}
+
+type TermExplanation struct {
+	Term     string  `json:"term"`
+	Matched  bool    `json:"matched"`
+	TermFreq int     `json:"term_freq"`
+	DocFreq  int     `json:"doc_freq"`
+	Weight   float64 `json:"weight"`
+	Score    float64 `json:"score"`
+}
+
+func comicsResponse(item core.Comics) Comics {
+	comics := Comics{Id: item.ID, Url: item.URL, Score: item.Score, Snippet: item.Snippet}
+	if item.Explain != nil {
+		comics.Explain = &Explanation{
+			Backend: item.Explain.Backend,
+			Terms:   make([]TermExplanation, 0, len(item.Explain.Terms)),
+		}
+		for _, t := range item.Explain.Terms {
+			comics.Explain.Terms = append(comics.Explain.Terms, TermExplanation{
+				Term:     t.Term,
+				Matched:  t.Matched,
+				TermFreq: t.TermFreq,
+				DocFreq:  t.DocFreq,
+				Weight:   t.Weight,
+				Score:    t.Score,
+			})
+		}
+	}
+	return comics
 }
+
+// SearchResponse.Query is the query as the search service understood it:
+// words are normalized, "quoted phrases" match words in exact order,
+// a NEAR/n b matches a and b at most n words apart, +required and -excluded
+// parts, OR and (groups) are kept, other parts are combined with OR.
+// Expansion is an unknown query word replaced with a similar indexed one.
+type Expansion struct {
+	Word     string `json:"word"`
+	Term     string `json:"term"`
+	Distance int    `json:"distance"`
+}
+
 type SearchResponse struct {
-	Comics []Comics `json:"comics"`
-	Total  int      `json:"total"`
+	Comics     []Comics    `json:"comics"`
+	Total      int         `json:"total"`
+	Query      string      `json:"query"`
+	Expansions []Expansion `json:"expansions"`
+	// Suggestion is a corrected query, set only when nothing was found.
+	Suggestion string `json:"suggestion,omitempty"`
+	// NextCursor requests the next page, it is empty on the last one.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 const defaultLimit = 10
 
+type searchFunc func(ctx context.Context, query core.SearchQuery) (core.SearchResult, error)
+
+// NewSearchHandler searches comics in the database, with stream=1
+// the result is sent as NDJSON: the first line is the response without
+// comics, every next line is a found comic.
 func NewSearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	search := newSearchHandler(log, searcher.Search)
+	stream := newSearchStreamHandler(log, searcher)
 	return func(w http.ResponseWriter, r *http.Request) {
-		var (
-			limit int
-			err   error
-		)
-		limitStr := r.URL.Query().Get("limit")
-		if limitStr != "" {
-			limit, err = strconv.Atoi(limitStr)
-			if err != nil {
-				log.Error("wrong limit", "value", limitStr)
-				http.Error(w, "bad limit", http.StatusBadRequest)
-				return
+		if r.URL.Query().Get("stream") == "1" {
+			stream(w, r)
+			return
+		}
+		search(w, r)
+	}
+}
+
+// NewSearchIndexHandler searches comics in the in-memory index.
+func NewSearchIndexHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return newSearchHandler(log, searcher.SearchIndex)
+}
+
+// parseSearchQuery reads the search query from the request,
+// the error is the reply to the client.
+func parseSearchQuery(log *slog.Logger, r *http.Request) (core.SearchQuery, error) {
+	var (
+		limit int
+		err   error
+	)
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			log.Error("wrong limit", "value", limitStr)
+			return core.SearchQuery{}, errors.New("bad limit")
+		}
+		if limit < 0 {
+			log.Error("wrong limit", "value", limitStr)
+			return core.SearchQuery{}, errors.New("bad limit")
+		}
+
+	} else {
+		limit = defaultLimit
+	}
+	var offset int
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			log.Error("wrong offset", "value", offsetStr)
+			return core.SearchQuery{}, errors.New("bad offset")
+		}
+	}
+	var explain bool
+	if explainStr := r.URL.Query().Get("explain"); explainStr != "" {
+		explain, err = strconv.ParseBool(explainStr)
+		if err != nil {
+			log.Error("wrong explain", "value", explainStr)
+			return core.SearchQuery{}, errors.New("bad explain")
+		}
+	}
+	phrase := r.URL.Query().Get("phrase")
+	if phrase == "" {
+		log.Error("no phrase")
+		return core.SearchQuery{}, errors.New("no phrase")
+	}
+	return core.SearchQuery{
+		Phrase:  phrase,
+		Limit:   limit,
+		Offset:  offset,
+		Cursor:  r.URL.Query().Get("cursor"),
+		Explain: explain,
+	}, nil
+}
+
+func searchError(log *slog.Logger, w http.ResponseWriter, err error) {
+	if errors.Is(err, core.ErrNotFound) {
+		http.Error(w, "no comics found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, core.ErrBadArguments) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Error("problems finding comics", "error", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func searchResponse(result core.SearchResult) SearchResponse {
+	response := SearchResponse{
+		Comics:     make([]Comics, 0, len(result.Comics)),
+		Total:      result.Total,
+		Query:      result.Query,
+		Expansions: make([]Expansion, 0, len(result.Expansions)),
+		Suggestion: result.Suggestion,
+		NextCursor: result.NextCursor,
+	}
+
+	for _, e := range result.Expansions {
+		response.Expansions = append(response.Expansions, Expansion{Word: e.Word, Term: e.Term, Distance: e.Distance})
+	}
+
+	for _, item := range result.Comics {
+		response.Comics = append(response.Comics, comicsResponse(item))
+	}
+	return response
+}
+
+func newSearchHandler(log *slog.Logger, search searchFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, err := parseSearchQuery(log, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err := search(r.Context(), query)
+		if err != nil {
+			searchError(log, w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(searchResponse(result)); err != nil {
+			log.Error("cannot encode reply", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+	}
+}
+
+// StreamError is the last NDJSON line when the search failed after
+// the stream had started.
+type StreamError struct {
+	Error string `json:"error"`
+}
+
+func newSearchStreamHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, err := parseSearchQuery(log, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		started := false
+		encoder := json.NewEncoder(w)
+		flusher := http.NewResponseController(w)
+		err = searcher.SearchStream(r.Context(), query, func(result core.SearchResult) error {
+			if !started {
+				started = true
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				if err := encoder.Encode(searchResponse(result)); err != nil {
+					return err
+				}
 			}
-			if limit < 0 {
+			for _, item := range result.Comics {
+				if err := encoder.Encode(comicsResponse(item)); err != nil {
+					return err
+				}
+			}
+			if err := flusher.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+			return nil
+		})
+		if err == nil {
+			return
+		}
+		if !started {
+			searchError(log, w, err)
+			return
+		}
+		log.Error("search stream broke", "error", err)
+		if err := encoder.Encode(StreamError{Error: err.Error()}); err != nil {
+			log.Error("cannot encode reply", "error", err)
+		}
+	}
+}
+
+type Completion struct {
+	Word    string `json:"word"`
+	DocFreq int    `json:"doc_freq"`
+}
+
+type SuggestResponse struct {
+	Completions []Completion `json:"completions"`
+}
+
+// NewSuggestHandler completes a word prefix with indexed words,
+// the ones found in most comics first.
+func NewSuggestHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultLimit
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			var err error
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
 				log.Error("wrong limit", "value", limitStr)
 				http.Error(w, "bad limit", http.StatusBadRequest)
 				return
 			}
-
-		} else {
-			limit = defaultLimit
 		}
-		phrase := r.URL.Query().Get("phrase")
-		if phrase == "" {
-			log.Error("no phrase")
-			http.Error(w, "no phrase", http.StatusBadRequest)
+		prefix := r.URL.Query().Get("prefix")
+		if prefix == "" {
+			log.Error("no prefix")
+			http.Error(w, "no prefix", http.StatusBadRequest)
 			return
 		}
-		comics, err := searcher.Search(r.Context(), phrase, limit)
+		completions, err := searcher.Suggest(r.Context(), prefix, limit)
 		if err != nil {
-			if errors.Is(err, core.ErrNotFound) {
-				http.Error(w, "no comics found", http.StatusNotFound)
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
-			log.Error("problems finding comics", "error", err)
+			log.Error("problems completing prefix", "error", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		response := SearchResponse{
-			Comics: make([]Comics, 0),
-			Total:  len(comics),
-		}
-
-		for _, item := range comics {
-			response.Comics = append(response.Comics, Comics{Id: item.ID, Url: item.URL})
+		response := SuggestResponse{Completions: make([]Completion, 0, len(completions))}
+		for _, c := range completions {
+			response.Completions = append(response.Completions, Completion{Word: c.Word, DocFreq: c.DocFreq})
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -224,57 +509,156 @@ func NewSearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
-
 	}
 }
 
-func NewSearchIndexHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+type SimilarResponse struct {
+	Comics []Comics `json:"comics"`
+}
+
+// NewSimilarHandler finds comics sharing the most distinctive words
+// with the comic from the path.
+func NewSimilarHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var (
-			limit int
-			err   error
-		)
-		limitStr := r.URL.Query().Get("limit")
-		if limitStr != "" {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil || id <= 0 {
+			log.Error("wrong comic id", "value", r.PathValue("id"))
+			http.Error(w, "bad id", http.StatusBadRequest)
+			return
+		}
+		limit := defaultLimit
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 			limit, err = strconv.Atoi(limitStr)
-			if err != nil {
+			if err != nil || limit <= 0 {
 				log.Error("wrong limit", "value", limitStr)
 				http.Error(w, "bad limit", http.StatusBadRequest)
 				return
 			}
-			if limit < 0 {
-				log.Error("wrong limit", "value", limitStr)
-				http.Error(w, "bad limit", http.StatusBadRequest)
-				return
-			}
-
-		} else {
-			limit = defaultLimit
 		}
-		phrase := r.URL.Query().Get("phrase")
-		if phrase == "" {
-			log.Error("no phrase")
-			http.Error(w, "no phrase", http.StatusBadRequest)
-			return
-		}
-		comics, err := searcher.Search(r.Context(), phrase, limit)
+		comics, err := searcher.Similar(r.Context(), id, limit)
 		if err != nil {
 			if errors.Is(err, core.ErrNotFound) {
-				http.Error(w, "no comics found", http.StatusNotFound)
+				http.Error(w, "comic not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
-			log.Error("problems finding comics", "error", err)
+			log.Error("problems finding similar comics", "error", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		response := SearchResponse{
-			Comics: make([]Comics, 0),
-			Total:  len(comics),
+		response := SimilarResponse{Comics: make([]Comics, 0, len(comics))}
+		for _, item := range comics {
+			response.Comics = append(response.Comics, comicsResponse(item))
 		}
 
-		for _, item := range comics {
-			response.Comics = append(response.Comics, Comics{Id: item.ID, Url: item.URL})
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error("cannot encode reply", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+type ComicResponse struct {
+	Id         int    `json:"id"`
+	Url        string `json:"url"`
+	Title      string `json:"title"`
+	Alt        string `json:"alt"`
+	Transcript string `json:"transcript"`
+	// Published is a date like 2006-01-02, comics fetched before dates
+	// were stored have none.
+	Published string `json:"published,omitempty"`
+}
+
+func NewComicHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil || id <= 0 {
+			log.Error("wrong comic id", "value", r.PathValue("id"))
+			http.Error(w, "bad id", http.StatusBadRequest)
+			return
+		}
+		comics, err := searcher.GetComic(r.Context(), id)
+		writeComic(log, w, comics, err)
+	}
+}
+
+// NewRandomComicHandler picks a random comic, among the comics found
+// by the phrase parameter if it is given.
+func NewRandomComicHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		comics, err := searcher.Random(r.Context(), r.URL.Query().Get("phrase"))
+		writeComic(log, w, comics, err)
+	}
+}
+
+func NewLatestComicHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		comics, err := searcher.Latest(r.Context())
+		writeComic(log, w, comics, err)
+	}
+}
+
+// writeComic replies with the comic or with the error of getting it.
+func writeComic(log *slog.Logger, w http.ResponseWriter, comics core.ComicDetails, err error) {
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			http.Error(w, "comic not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, core.ErrBadArguments) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Error("problems getting comic", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ComicResponse{
+		Id:         comics.ID,
+		Url:        comics.URL,
+		Title:      comics.Title,
+		Alt:        comics.Alt,
+		Transcript: comics.Transcript,
+	}
+	if !comics.Published.IsZero() {
+		response.Published = comics.Published.Format(time.DateOnly)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Error("cannot encode reply", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+}
+
+type SynonymGroup struct {
+	Words []string `json:"words"`
+}
+
+type SynonymsResponse struct {
+	Groups []SynonymGroup `json:"groups"`
+}
+
+func NewSynonymsHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		groups, err := searcher.Synonyms(r.Context())
+		if err != nil {
+			log.Error("problems getting synonyms", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		response := SynonymsResponse{Groups: make([]SynonymGroup, 0, len(groups))}
+		for _, g := range groups {
+			response.Groups = append(response.Groups, SynonymGroup{Words: g.Words})
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -283,6 +667,65 @@ func NewSearchIndexHandler(log *slog.Logger, searcher core.Searcher) http.Handle
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
+	}
+}
+
+// NewAddSynonymsHandler adds a group of words, a word may be
+// in one group only.
+func NewAddSynonymsHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var group SynonymGroup
+		if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+			log.Error("could not decode synonyms", "error", err)
+			http.Error(w, "could not parse synonyms", http.StatusBadRequest)
+			return
+		}
+
+		err := searcher.AddSynonyms(r.Context(), core.SynonymGroup{Words: group.Words})
+		if err != nil {
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, core.ErrAlreadyExists) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			log.Error("problems adding synonyms", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}
+}
 
+// NewRemoveSynonymsHandler removes the group with the word from the path.
+func NewRemoveSynonymsHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := searcher.RemoveSynonyms(r.Context(), r.PathValue("word"))
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				http.Error(w, "no synonyms of the word", http.StatusNotFound)
+				return
+			}
+			log.Error("problems removing synonyms", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// NewReloadSynonymsHandler makes the search service read the synonyms
+// file again.
+func NewReloadSynonymsHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := searcher.ReloadSynonyms(r.Context())
+		if err != nil {
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Error("problems reloading synonyms", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
 	}
 }
@@ -1,14 +1,19 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	core2 "yadro.com/course/api/core"
 	mock_core "yadro.com/course/api/core/mocks"
@@ -144,3 +149,686 @@ func TestNewUpdateStatusHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSearchHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		url                  string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success",
+			url:  "/api/search?phrase=cat&limit=2",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Search(gomock.Any(), core2.SearchQuery{Phrase: "cat", Limit: 2}).Return(core2.SearchResult{
+					Comics: []core2.Comics{{ID: 1, URL: "a.png", Score: 2.5, Snippet: "a <b>cat</b>"}, {ID: 7, URL: "b.png", Score: 1}},
+					Query:  "cat",
+					Total:  2,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"comics": [{"id": 1, "url": "a.png", "score": 2.5, "snippet": "a <b>cat</b>"}, {"id": 7, "url": "b.png", "score": 1}],
+				"total": 2,
+				"query": "cat",
+				"expansions": []
+			}`,
+		},
+		{
+			name: "Next Page",
+			url:  "/api/search?phrase=cat&limit=1&cursor=abc",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Search(gomock.Any(), core2.SearchQuery{Phrase: "cat", Limit: 1, Cursor: "abc"}).Return(core2.SearchResult{
+					Comics:     []core2.Comics{{ID: 7, URL: "b.png", Score: 1}},
+					Query:      "cat",
+					Total:      3,
+					NextCursor: "def",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"comics": [{"id": 7, "url": "b.png", "score": 1}],
+				"total": 3,
+				"query": "cat",
+				"expansions": [],
+				"next_cursor": "def"
+			}`,
+		},
+		{
+			name: "Explain",
+			url:  "/api/search?phrase=cat+dog&explain=true",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Search(gomock.Any(), core2.SearchQuery{Phrase: "cat dog", Limit: defaultLimit, Explain: true}).Return(core2.SearchResult{
+					Comics: []core2.Comics{{ID: 1, URL: "a.png", Score: 1.5, Explain: &core2.Explanation{
+						Backend: "db:array",
+						Terms: []core2.TermExplanation{
+							{Term: "cat", Matched: true, TermFreq: 2, DocFreq: 10, Weight: 1, Score: 1.5},
+							{Term: "dog", DocFreq: 4, Weight: 1},
+						},
+					}}},
+					Query: "cat OR dog",
+					Total: 1,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"comics": [{"id": 1, "url": "a.png", "score": 1.5, "explain": {
+					"backend": "db:array",
+					"terms": [
+						{"term": "cat", "matched": true, "term_freq": 2, "doc_freq": 10, "weight": 1, "score": 1.5},
+						{"term": "dog", "matched": false, "term_freq": 0, "doc_freq": 4, "weight": 1, "score": 0}
+					]
+				}}],
+				"total": 1,
+				"query": "cat OR dog",
+				"expansions": []
+			}`,
+		},
+		{
+			name:                 "Bad Explain",
+			url:                  "/api/search?phrase=cat&explain=maybe",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "bad explain\n",
+		},
+		{
+			name:                 "Bad Offset",
+			url:                  "/api/search?phrase=cat&offset=-1",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "bad offset\n",
+		},
+		{
+			name: "Bad Query",
+			url:  "/api/search?phrase=%28cat",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Search(gomock.Any(), core2.SearchQuery{Phrase: "(cat", Limit: defaultLimit}).
+					Return(core2.SearchResult{}, fmt.Errorf("%w: missing closing parenthesis", core2.ErrBadArguments))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "arguments are not acceptable: missing closing parenthesis\n",
+		},
+		{
+			name:                 "No Phrase",
+			url:                  "/api/search",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "no phrase\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			logger := slog.Default()
+
+			handler := NewSearchHandler(logger, mockSearcher)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedStatusCode == http.StatusOK {
+				var expected, actual map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(tt.expectedResponseBody), &expected))
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+				assert.Equal(t, expected, actual)
+
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewSearchIndexHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no Search expectation: the database search must not be called
+	mockSearcher := mock_core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().SearchIndex(gomock.Any(), core2.SearchQuery{Phrase: "cat", Limit: 1}).Return(core2.SearchResult{
+		Comics: []core2.Comics{{ID: 3, URL: "c.png", Score: 1.5}},
+		Query:  "cat",
+		Total:  1,
+	}, nil)
+
+	handler := NewSearchIndexHandler(slog.Default(), mockSearcher)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/isearch?phrase=cat&limit=1", nil)
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"comics": [{"id": 3, "url": "c.png", "score": 1.5}],
+		"total": 1,
+		"query": "cat",
+		"expansions": []
+	}`, w.Body.String())
+}
+
+func TestNewIndexStatusHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().IndexStatus(gomock.Any()).Return(core2.IndexStatus{
+					Status:     core2.StatusIndexIdle,
+					LastBuild:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+					Duration:   1500 * time.Millisecond,
+					Comics:     3000,
+					Vocabulary: 12000,
+					Cache:      core2.CacheStats{Hits: 40, Misses: 10, Size: 8},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"status": "idle",
+				"last_build": "2024-05-01T12:00:00Z",
+				"duration_ms": 1500,
+				"comics": 3000,
+				"vocabulary": 12000,
+				"cache": {"hits": 40, "misses": 10, "size": 8}
+			}`,
+		},
+		{
+			name: "Never Built",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().IndexStatus(gomock.Any()).Return(core2.IndexStatus{
+					Status:    core2.StatusIndexRunning,
+					LastError: "database is down",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"status": "running",
+				"duration_ms": 0,
+				"comics": 0,
+				"vocabulary": 0,
+				"last_error": "database is down",
+				"cache": {"hits": 0, "misses": 0, "size": 0}
+			}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().IndexStatus(gomock.Any()).Return(core2.IndexStatus{}, errors.New("search is unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: "search is unavailable\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			handler := NewIndexStatusHandler(slog.Default(), mockSearcher)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/index/status", nil)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.JSONEq(t, tt.expectedResponseBody, w.Body.String())
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewSimilarHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		url                  string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success",
+			url:  "/api/comics/927/similar?limit=2",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Similar(gomock.Any(), 927, 2).Return([]core2.Comics{{ID: 1, URL: "a.png", Score: 2.5}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"comics": [{"id": 1, "url": "a.png", "score": 2.5}]}`,
+		},
+		{
+			name: "Not Found",
+			url:  "/api/comics/100500/similar",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Similar(gomock.Any(), 100500, defaultLimit).Return(nil, core2.ErrNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: "comic not found\n",
+		},
+		{
+			name:                 "Bad ID",
+			url:                  "/api/comics/abc/similar",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "bad id\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			mux := http.NewServeMux()
+			mux.Handle("GET /api/comics/{id}/similar", NewSimilarHandler(slog.Default(), mockSearcher))
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.JSONEq(t, tt.expectedResponseBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewAddSynonymsHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Created",
+			body: `{"words": ["car", "automobile"]}`,
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().AddSynonyms(gomock.Any(), core2.SynonymGroup{Words: []string{"car", "automobile"}}).Return(nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name: "Conflict",
+			body: `{"words": ["car", "auto"]}`,
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().AddSynonyms(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("%w: \"car\" is in a group already", core2.ErrAlreadyExists))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: "resource or task already exists: \"car\" is in a group already\n",
+		},
+		{
+			name:                 "Bad Body",
+			body:                 `["car"]`,
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "could not parse synonyms\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			handler := NewAddSynonymsHandler(slog.Default(), mockSearcher)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/synonyms", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestNewSearchHandlerStream(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().SearchStream(gomock.Any(), core2.SearchQuery{Phrase: "cat", Limit: 10000}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ core2.SearchQuery, send func(core2.SearchResult) error) error {
+						require.NoError(t, send(core2.SearchResult{Total: 2, Query: "cat"}))
+						return send(core2.SearchResult{Comics: []core2.Comics{{ID: 1, URL: "a.png", Score: 2}, {ID: 2, URL: "b.png", Score: 1}}})
+					})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"comics":[],"total":2,"query":"cat","expansions":[]}
+{"id":1,"url":"a.png","score":2}
+{"id":2,"url":"b.png","score":1}
+`,
+		},
+		{
+			name: "Bad Query",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().SearchStream(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("%w: unclosed quote", core2.ErrBadArguments))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "arguments are not acceptable: unclosed quote\n",
+		},
+		{
+			name: "Broken Stream",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().SearchStream(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ core2.SearchQuery, send func(core2.SearchResult) error) error {
+						require.NoError(t, send(core2.SearchResult{Total: 2, Query: "cat"}))
+						return errors.New("search is unavailable")
+					})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"comics":[],"total":2,"query":"cat","expansions":[]}
+{"error":"search is unavailable"}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			handler := NewSearchHandler(slog.Default(), mockSearcher)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/search?phrase=cat&limit=10000&stream=1", nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestNewComicHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		url                  string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success",
+			url:  "/api/comics/327",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().GetComic(gomock.Any(), 327).Return(core2.ComicDetails{
+					ID:         327,
+					URL:        "exploits_of_a_mom.png",
+					Title:      "Exploits of a Mom",
+					Alt:        "Her daughter is named Help I'm trapped in a driver's license factory.",
+					Transcript: "Hi, this is your son's school.",
+					Published:  time.Date(2007, 10, 10, 0, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"id": 327,
+				"url": "exploits_of_a_mom.png",
+				"title": "Exploits of a Mom",
+				"alt": "Her daughter is named Help I'm trapped in a driver's license factory.",
+				"transcript": "Hi, this is your son's school.",
+				"published": "2007-10-10"
+			}`,
+		},
+		{
+			name: "Not Found",
+			url:  "/api/comics/100500",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().GetComic(gomock.Any(), 100500).Return(core2.ComicDetails{}, core2.ErrNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: "comic not found\n",
+		},
+		{
+			name:                 "Bad ID",
+			url:                  "/api/comics/-1",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "bad id\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			mux := http.NewServeMux()
+			mux.Handle("GET /api/comics/{id}", NewComicHandler(slog.Default(), mockSearcher))
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.JSONEq(t, tt.expectedResponseBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewRandomAndLatestComicHandlers(t *testing.T) {
+	tests := []struct {
+		name                 string
+		url                  string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Random",
+			url:  "/api/comics/random",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Random(gomock.Any(), "").Return(core2.ComicDetails{ID: 149, URL: "sandwich.png"}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 149, "url": "sandwich.png", "title": "", "alt": "", "transcript": ""}`,
+		},
+		{
+			name: "Random By Phrase",
+			url:  "/api/comics/random?phrase=cat",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Random(gomock.Any(), "cat").Return(core2.ComicDetails{ID: 231, URL: "cat_proximity.png"}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 231, "url": "cat_proximity.png", "title": "", "alt": "", "transcript": ""}`,
+		},
+		{
+			name: "Random Nothing Found",
+			url:  "/api/comics/random?phrase=qwerty",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Random(gomock.Any(), "qwerty").Return(core2.ComicDetails{}, core2.ErrNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: "comic not found\n",
+		},
+		{
+			name: "Latest",
+			url:  "/api/comics/latest",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Latest(gomock.Any()).Return(core2.ComicDetails{
+					ID:        3000,
+					URL:       "latest.png",
+					Published: time.Date(2024, 11, 4, 0, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"id": 3000,
+				"url": "latest.png",
+				"title": "",
+				"alt": "",
+				"transcript": "",
+				"published": "2024-11-04"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			mux := http.NewServeMux()
+			mux.Handle("GET /api/comics/{id}", NewComicHandler(slog.Default(), mockSearcher))
+			mux.Handle("GET /api/comics/random", NewRandomComicHandler(slog.Default(), mockSearcher))
+			mux.Handle("GET /api/comics/latest", NewLatestComicHandler(slog.Default(), mockSearcher))
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.JSONEq(t, tt.expectedResponseBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewSuggestHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		url                  string
+		mockBehavior         func(searcher *mock_core.MockSearcher)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success",
+			url:  "/api/suggest?prefix=pyth&limit=2",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Suggest(gomock.Any(), "pyth", 2).Return([]core2.Completion{
+					{Word: "python", DocFreq: 12},
+					{Word: "pythagorean", DocFreq: 2},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"completions": [{"word": "python", "doc_freq": 12}, {"word": "pythagorean", "doc_freq": 2}]}`,
+		},
+		{
+			name: "Default Limit",
+			url:  "/api/suggest?prefix=zzz",
+			mockBehavior: func(m *mock_core.MockSearcher) {
+				m.EXPECT().Suggest(gomock.Any(), "zzz", defaultLimit).Return(nil, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"completions": []}`,
+		},
+		{
+			name:                 "Zero Limit",
+			url:                  "/api/suggest?prefix=pyth&limit=0",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "bad limit\n",
+		},
+		{
+			name:                 "Negative Limit",
+			url:                  "/api/suggest?prefix=pyth&limit=-1",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "bad limit\n",
+		},
+		{
+			name:                 "Bad Limit",
+			url:                  "/api/suggest?prefix=pyth&limit=abc",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "bad limit\n",
+		},
+		{
+			name:                 "No Prefix",
+			url:                  "/api/suggest?limit=5",
+			mockBehavior:         func(m *mock_core.MockSearcher) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "no prefix\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mock_core.NewMockSearcher(ctrl)
+			tt.mockBehavior(mockSearcher)
+
+			handler := NewSuggestHandler(slog.Default(), mockSearcher)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.JSONEq(t, tt.expectedResponseBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
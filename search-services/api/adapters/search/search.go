@@ -2,6 +2,9 @@ package search
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 
 	"google.golang.org/grpc"
@@ -34,36 +37,229 @@ func (c Client) Ping(ctx context.Context) error {
 	return err
 }
 
-func (c Client) Search(ctx context.Context, phrase string, limit int) ([]core.Comics, error) {
-	reply, err := c.client.Search(ctx, &searchpb.SearchRequest{Keywords: phrase, Limit: int64(limit)})
+func (c Client) Search(ctx context.Context, query core.SearchQuery) (core.SearchResult, error) {
+	reply, err := c.client.Search(ctx, searchRequest(query))
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			return nil, core.ErrNotFound
+		return core.SearchResult{}, searchError(err)
+	}
+	return searchResult(reply), nil
+
+}
+
+func (c Client) SearchIndex(ctx context.Context, query core.SearchQuery) (core.SearchResult, error) {
+	reply, err := c.client.SearchIndex(ctx, searchRequest(query))
+	if err != nil {
+		return core.SearchResult{}, searchError(err)
+	}
+	return searchResult(reply), nil
+
+}
+
+func (c Client) SearchStream(ctx context.Context, query core.SearchQuery, send func(core.SearchResult) error) error {
+	stream, err := c.client.SearchStream(ctx, searchRequest(query))
+	if err != nil {
+		return searchError(err)
+	}
+	for {
+		reply, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
 		}
-		return nil, err
+		if err != nil {
+			return searchError(err)
+		}
+		if err := send(searchResult(reply)); err != nil {
+			return err
+		}
+	}
+}
 
+func (c Client) Suggest(ctx context.Context, prefix string, limit int) ([]core.Completion, error) {
+	reply, err := c.client.Suggest(ctx, &searchpb.SuggestRequest{Prefix: prefix, Limit: int64(limit)})
+	if err != nil {
+		return nil, searchError(err)
 	}
-	comics := make([]core.Comics, 0)
-	for _, item := range reply.Comics {
-		comics = append(comics, core.Comics{ID: int(item.Id), URL: item.Url})
+	completions := make([]core.Completion, 0, len(reply.Completions))
+	for _, item := range reply.Completions {
+		completions = append(completions, core.Completion{Word: item.Word, DocFreq: int(item.DocFreq)})
 	}
-	return comics, nil
+	return completions, nil
+}
 
+func (c Client) Similar(ctx context.Context, id, limit int) ([]core.Comics, error) {
+	reply, err := c.client.Similar(ctx, &searchpb.SimilarRequest{Id: int64(id), Limit: int64(limit)})
+	if err != nil {
+		return nil, searchError(err)
+	}
+	return comicsResult(reply.Comics), nil
 }
 
-func (c Client) SearchIndex(ctx context.Context, phrase string, limit int) ([]core.Comics, error) {
-	reply, err := c.client.Search(ctx, &searchpb.SearchRequest{Keywords: phrase, Limit: int64(limit)})
+func (c Client) GetComic(ctx context.Context, id int) (core.ComicDetails, error) {
+	reply, err := c.client.GetComic(ctx, &searchpb.GetComicRequest{Id: int64(id)})
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			return nil, core.ErrNotFound
-		}
-		return nil, err
+		return core.ComicDetails{}, searchError(err)
+	}
+	return comicDetails(reply), nil
+}
 
+func (c Client) Random(ctx context.Context, phrase string) (core.ComicDetails, error) {
+	reply, err := c.client.Random(ctx, &searchpb.RandomRequest{Phrase: phrase})
+	if err != nil {
+		return core.ComicDetails{}, searchError(err)
 	}
-	comics := make([]core.Comics, 0)
-	for _, item := range reply.Comics {
-		comics = append(comics, core.Comics{ID: int(item.Id), URL: item.Url})
+	return comicDetails(reply), nil
+}
+
+func (c Client) Latest(ctx context.Context) (core.ComicDetails, error) {
+	reply, err := c.client.Latest(ctx, &emptypb.Empty{})
+	if err != nil {
+		return core.ComicDetails{}, searchError(err)
+	}
+	return comicDetails(reply), nil
+}
+
+func comicDetails(reply *searchpb.ComicReply) core.ComicDetails {
+	comics := core.ComicDetails{
+		ID:         int(reply.Id),
+		URL:        reply.Url,
+		Title:      reply.Title,
+		Alt:        reply.Alt,
+		Transcript: reply.Transcript,
 	}
-	return comics, nil
+	if reply.Published != nil {
+		comics.Published = reply.Published.AsTime()
+	}
+	return comics
+}
 
+func (c Client) IndexStatus(ctx context.Context) (core.IndexStatus, error) {
+	reply, err := c.client.IndexStatus(ctx, &emptypb.Empty{})
+	if err != nil {
+		return core.IndexStatus{Status: core.StatusIndexUnknown}, err
+	}
+
+	indexStatus := core.IndexStatus{
+		Status:     core.StatusIndexUnknown,
+		Duration:   reply.Duration.AsDuration(),
+		Comics:     int(reply.Comics),
+		Vocabulary: int(reply.Vocabulary),
+		LastError:  reply.LastError,
+		Cache: core.CacheStats{
+			Hits:   int(reply.GetCache().GetHits()),
+			Misses: int(reply.GetCache().GetMisses()),
+			Size:   int(reply.GetCache().GetSize()),
+		},
+	}
+	switch reply.Status {
+	case searchpb.Status_STATUS_IDLE:
+		indexStatus.Status = core.StatusIndexIdle
+	case searchpb.Status_STATUS_RUNNING:
+		indexStatus.Status = core.StatusIndexRunning
+	}
+	if reply.LastBuild != nil {
+		indexStatus.LastBuild = reply.LastBuild.AsTime()
+	}
+	return indexStatus, nil
+}
+
+func (c Client) Synonyms(ctx context.Context) ([]core.SynonymGroup, error) {
+	reply, err := c.client.Synonyms(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, searchError(err)
+	}
+	groups := make([]core.SynonymGroup, 0, len(reply.Groups))
+	for _, g := range reply.Groups {
+		groups = append(groups, core.SynonymGroup{Words: g.Words})
+	}
+	return groups, nil
+}
+
+func (c Client) AddSynonyms(ctx context.Context, group core.SynonymGroup) error {
+	_, err := c.client.AddSynonyms(ctx, &searchpb.SynonymGroup{Words: group.Words})
+	return searchError(err)
+}
+
+func (c Client) RemoveSynonyms(ctx context.Context, word string) error {
+	_, err := c.client.RemoveSynonyms(ctx, &searchpb.RemoveSynonymsRequest{Word: word})
+	return searchError(err)
+}
+
+func (c Client) ReloadSynonyms(ctx context.Context) error {
+	_, err := c.client.ReloadSynonyms(ctx, &emptypb.Empty{})
+	return searchError(err)
+}
+
+func searchError(err error) error {
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound:
+		return core.ErrNotFound
+	case codes.AlreadyExists:
+		return fmt.Errorf("%w: %s", core.ErrAlreadyExists, status.Convert(err).Message())
+	case codes.InvalidArgument:
+		return fmt.Errorf("%w: %s", core.ErrBadArguments, status.Convert(err).Message())
+	}
+	return err
+}
+
+func searchRequest(query core.SearchQuery) *searchpb.SearchRequest {
+	return &searchpb.SearchRequest{
+		Keywords: query.Phrase,
+		Limit:    int64(query.Limit),
+		Offset:   int64(query.Offset),
+		Cursor:   query.Cursor,
+		Explain:  query.Explain,
+	}
+}
+
+func comicsResult(found []*searchpb.Comics) []core.Comics {
+	comics := make([]core.Comics, 0, len(found))
+	for _, item := range found {
+		comics = append(comics, core.Comics{
+			ID:      int(item.Id),
+			URL:     item.Url,
+			Score:   item.Score,
+			Snippet: item.Snippet,
+			Explain: explanation(item.Explain),
+		})
+	}
+	return comics
+}
+
+func explanation(reply *searchpb.Explanation) *core.Explanation {
+	if reply == nil {
+		return nil
+	}
+	explain := &core.Explanation{
+		Backend: reply.Backend,
+		Terms:   make([]core.TermExplanation, 0, len(reply.Terms)),
+	}
+	for _, t := range reply.Terms {
+		explain.Terms = append(explain.Terms, core.TermExplanation{
+			Term:     t.Term,
+			Matched:  t.Matched,
+			TermFreq: int(t.TermFreq),
+			DocFreq:  int(t.DocFreq),
+			Weight:   t.Weight,
+			Score:    t.Score,
+		})
+	}
+	return explain
+}
+
+func searchResult(reply *searchpb.SearchReply) core.SearchResult {
+	comics := comicsResult(reply.Comics)
+	expansions := make([]core.Expansion, 0, len(reply.Expansions))
+	for _, e := range reply.Expansions {
+		expansions = append(expansions, core.Expansion{Word: e.Word, Term: e.Term, Distance: int(e.Distance)})
+	}
+	return core.SearchResult{
+		Comics:     comics,
+		Query:      reply.Query,
+		Expansions: expansions,
+		Suggestion: reply.Suggestion,
+		Total:      int(reply.Total),
+		NextCursor: reply.NextCursor,
+	}
 }
@@ -200,32 +200,193 @@ func (m *MockSearcher) EXPECT() *MockSearcherMockRecorder {
 	return m.recorder
 }
 
+// AddSynonyms mocks base method.
+func (m *MockSearcher) AddSynonyms(arg0 context.Context, arg1 core.SynonymGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSynonyms", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSynonyms indicates an expected call of AddSynonyms.
+func (mr *MockSearcherMockRecorder) AddSynonyms(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSynonyms", reflect.TypeOf((*MockSearcher)(nil).AddSynonyms), arg0, arg1)
+}
+
+// GetComic mocks base method.
+func (m *MockSearcher) GetComic(ctx context.Context, id int) (core.ComicDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComic", ctx, id)
+	ret0, _ := ret[0].(core.ComicDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComic indicates an expected call of GetComic.
+func (mr *MockSearcherMockRecorder) GetComic(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComic", reflect.TypeOf((*MockSearcher)(nil).GetComic), ctx, id)
+}
+
+// IndexStatus mocks base method.
+func (m *MockSearcher) IndexStatus(arg0 context.Context) (core.IndexStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexStatus", arg0)
+	ret0, _ := ret[0].(core.IndexStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexStatus indicates an expected call of IndexStatus.
+func (mr *MockSearcherMockRecorder) IndexStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexStatus", reflect.TypeOf((*MockSearcher)(nil).IndexStatus), arg0)
+}
+
+// Latest mocks base method.
+func (m *MockSearcher) Latest(arg0 context.Context) (core.ComicDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", arg0)
+	ret0, _ := ret[0].(core.ComicDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockSearcherMockRecorder) Latest(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockSearcher)(nil).Latest), arg0)
+}
+
+// Random mocks base method.
+func (m *MockSearcher) Random(ctx context.Context, phrase string) (core.ComicDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Random", ctx, phrase)
+	ret0, _ := ret[0].(core.ComicDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Random indicates an expected call of Random.
+func (mr *MockSearcherMockRecorder) Random(ctx, phrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Random", reflect.TypeOf((*MockSearcher)(nil).Random), ctx, phrase)
+}
+
+// ReloadSynonyms mocks base method.
+func (m *MockSearcher) ReloadSynonyms(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReloadSynonyms", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReloadSynonyms indicates an expected call of ReloadSynonyms.
+func (mr *MockSearcherMockRecorder) ReloadSynonyms(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadSynonyms", reflect.TypeOf((*MockSearcher)(nil).ReloadSynonyms), arg0)
+}
+
+// RemoveSynonyms mocks base method.
+func (m *MockSearcher) RemoveSynonyms(ctx context.Context, word string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSynonyms", ctx, word)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSynonyms indicates an expected call of RemoveSynonyms.
+func (mr *MockSearcherMockRecorder) RemoveSynonyms(ctx, word any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSynonyms", reflect.TypeOf((*MockSearcher)(nil).RemoveSynonyms), ctx, word)
+}
+
 // Search mocks base method.
-func (m *MockSearcher) Search(arg0 context.Context, arg1 string, arg2 int) ([]core.Comics, error) {
+func (m *MockSearcher) Search(arg0 context.Context, arg1 core.SearchQuery) (core.SearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]core.Comics)
+	ret := m.ctrl.Call(m, "Search", arg0, arg1)
+	ret0, _ := ret[0].(core.SearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockSearcherMockRecorder) Search(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockSearcherMockRecorder) Search(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearcher)(nil).Search), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearcher)(nil).Search), arg0, arg1)
 }
 
 // SearchIndex mocks base method.
-func (m *MockSearcher) SearchIndex(arg0 context.Context, arg1 string, arg2 int) ([]core.Comics, error) {
+func (m *MockSearcher) SearchIndex(arg0 context.Context, arg1 core.SearchQuery) (core.SearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchIndex", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]core.Comics)
+	ret := m.ctrl.Call(m, "SearchIndex", arg0, arg1)
+	ret0, _ := ret[0].(core.SearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchIndex indicates an expected call of SearchIndex.
-func (mr *MockSearcherMockRecorder) SearchIndex(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockSearcherMockRecorder) SearchIndex(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchIndex", reflect.TypeOf((*MockSearcher)(nil).SearchIndex), arg0, arg1)
+}
+
+// SearchStream mocks base method.
+func (m *MockSearcher) SearchStream(ctx context.Context, query core.SearchQuery, send func(core.SearchResult) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchStream", ctx, query, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SearchStream indicates an expected call of SearchStream.
+func (mr *MockSearcherMockRecorder) SearchStream(ctx, query, send any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchStream", reflect.TypeOf((*MockSearcher)(nil).SearchStream), ctx, query, send)
+}
+
+// Similar mocks base method.
+func (m *MockSearcher) Similar(ctx context.Context, id, limit int) ([]core.Comics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Similar", ctx, id, limit)
+	ret0, _ := ret[0].([]core.Comics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Similar indicates an expected call of Similar.
+func (mr *MockSearcherMockRecorder) Similar(ctx, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Similar", reflect.TypeOf((*MockSearcher)(nil).Similar), ctx, id, limit)
+}
+
+// Suggest mocks base method.
+func (m *MockSearcher) Suggest(arg0 context.Context, arg1 string, arg2 int) ([]core.Completion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]core.Completion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockSearcherMockRecorder) Suggest(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockSearcher)(nil).Suggest), arg0, arg1, arg2)
+}
+
+// Synonyms mocks base method.
+func (m *MockSearcher) Synonyms(arg0 context.Context) ([]core.SynonymGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Synonyms", arg0)
+	ret0, _ := ret[0].([]core.SynonymGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Synonyms indicates an expected call of Synonyms.
+func (mr *MockSearcherMockRecorder) Synonyms(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchIndex", reflect.TypeOf((*MockSearcher)(nil).SearchIndex), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Synonyms", reflect.TypeOf((*MockSearcher)(nil).Synonyms), arg0)
 }
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type UpdateStatus string
 
 const (
@@ -8,6 +10,31 @@ const (
 	StatusUpdateRunning UpdateStatus = "running"
 )
 
+type IndexState string
+
+const (
+	StatusIndexUnknown IndexState = "unknown"
+	StatusIndexIdle    IndexState = "idle"
+	StatusIndexRunning IndexState = "running"
+)
+
+type IndexStatus struct {
+	Status     IndexState
+	LastBuild  time.Time
+	Duration   time.Duration
+	Comics     int
+	Vocabulary int
+	LastError  string
+	Cache      CacheStats
+}
+
+// CacheStats counts searches answered from the search result cache.
+type CacheStats struct {
+	Hits   int
+	Misses int
+	Size   int
+}
+
 type UpdateStats struct {
 	WordsTotal    int
 	WordsUnique   int
@@ -18,5 +45,69 @@ type UpdateStats struct {
 type Comics struct {
 	ID    int
 	URL   string
-	Score int
+	Score float64
+	// Snippet is a piece of the comic text with found words in <b>.
+	Snippet string
+	// Explain is set only when it was asked for.
+	Explain *Explanation
+}
+
+// ComicDetails is a comic with its texts, they are empty and Published
+// is zero for comics fetched before they were stored.
+type ComicDetails struct {
+	ID         int
+	URL        string
+	Title      string
+	Alt        string
+	Transcript string
+	Published  time.Time
+}
+
+// Explanation shows how the score of a found comic was made up.
+type Explanation struct {
+	Backend string
+	Terms   []TermExplanation
+}
+
+type TermExplanation struct {
+	Term     string
+	Matched  bool
+	TermFreq int
+	DocFreq  int
+	Weight   float64
+	Score    float64
+}
+
+type Expansion struct {
+	Word     string
+	Term     string
+	Distance int
+}
+
+type SearchQuery struct {
+	Phrase string
+	Limit  int
+	Offset int
+	Cursor string
+	// Explain asks to explain the score of every found comic.
+	Explain bool
+}
+
+type SearchResult struct {
+	Comics     []Comics
+	Query      string
+	Expansions []Expansion
+	Suggestion string
+	Total      int
+	NextCursor string
+}
+
+// SynonymGroup is a set of words searched in place of each other.
+type SynonymGroup struct {
+	Words []string
+}
+
+type Completion struct {
+	Word    string
+	DocFreq int
 }
@@ -20,6 +20,21 @@ type Updater interface {
 }
 
 type Searcher interface {
-	Search(context.Context, string, int) ([]Comics, error)
-	SearchIndex(context.Context, string, int) ([]Comics, error)
+	Search(context.Context, SearchQuery) (SearchResult, error)
+	SearchIndex(context.Context, SearchQuery) (SearchResult, error)
+	// SearchStream passes the result without comics to send first
+	// and then the found comics in rank order, a few at a time.
+	SearchStream(ctx context.Context, query SearchQuery, send func(SearchResult) error) error
+	Suggest(context.Context, string, int) ([]Completion, error)
+	Similar(ctx context.Context, id, limit int) ([]Comics, error)
+	GetComic(ctx context.Context, id int) (ComicDetails, error)
+	// Random picks among comics found by the phrase, among all comics
+	// if it is empty.
+	Random(ctx context.Context, phrase string) (ComicDetails, error)
+	Latest(context.Context) (ComicDetails, error)
+	IndexStatus(context.Context) (IndexStatus, error)
+	Synonyms(context.Context) ([]SynonymGroup, error)
+	AddSynonyms(context.Context, SynonymGroup) error
+	RemoveSynonyms(ctx context.Context, word string) error
+	ReloadSynonyms(context.Context) error
 }
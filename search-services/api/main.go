@@ -62,8 +62,18 @@ func main() {
 	mux.Handle("GET /api/db/stats", rest.NewUpdateStatsHandler(log, updateClient))
 	mux.Handle("GET /api/db/status", rest.NewUpdateStatusHandler(log, updateClient))
 	mux.Handle("DELETE /api/db", middleware.Auth(rest.NewDropHandler(log, updateClient), authService))
+	mux.Handle("GET /api/index/status", rest.NewIndexStatusHandler(log, searchClient))
 	mux.Handle("GET /api/search", middleware.Concurrency(rest.NewSearchHandler(log, searchClient), int64(cfg.SearchConcurrency)))
 	mux.Handle("GET /api/isearch", middleware.Rate(rest.NewSearchIndexHandler(log, searchClient), cfg.SearchRate))
+	mux.Handle("GET /api/suggest", middleware.Rate(rest.NewSuggestHandler(log, searchClient), cfg.SearchRate))
+	mux.Handle("GET /api/comics/{id}", middleware.Rate(rest.NewComicHandler(log, searchClient), cfg.SearchRate))
+	mux.Handle("GET /api/comics/random", middleware.Rate(rest.NewRandomComicHandler(log, searchClient), cfg.SearchRate))
+	mux.Handle("GET /api/comics/latest", middleware.Rate(rest.NewLatestComicHandler(log, searchClient), cfg.SearchRate))
+	mux.Handle("GET /api/comics/{id}/similar", middleware.Rate(rest.NewSimilarHandler(log, searchClient), cfg.SearchRate))
+	mux.Handle("GET /api/synonyms", middleware.Auth(rest.NewSynonymsHandler(log, searchClient), authService))
+	mux.Handle("POST /api/synonyms", middleware.Auth(rest.NewAddSynonymsHandler(log, searchClient), authService))
+	mux.Handle("DELETE /api/synonyms/{word}", middleware.Auth(rest.NewRemoveSynonymsHandler(log, searchClient), authService))
+	mux.Handle("POST /api/synonyms/reload", middleware.Auth(rest.NewReloadSynonymsHandler(log, searchClient), authService))
 
 	server := http.Server{
 		Addr:        cfg.HTTPConfig.Address,
@@ -2,9 +2,9 @@
 // versions:
 // 	protoc-gen-go v1.36.6
 // 	protoc        v6.30.2
-// source: search/search.proto
+// source: proto/search/search.proto
 
-package __
+package search
 
 import (
 	reflect "reflect"
@@ -13,7 +13,9 @@ import (
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -56,11 +58,11 @@ func (x Status) String() string {
 }
 
 func (Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_search_search_proto_enumTypes[0].Descriptor()
+	return file_proto_search_search_proto_enumTypes[0].Descriptor()
 }
 
 func (Status) Type() protoreflect.EnumType {
-	return &file_search_search_proto_enumTypes[0]
+	return &file_proto_search_search_proto_enumTypes[0]
 }
 
 func (x Status) Number() protoreflect.EnumNumber {
@@ -69,20 +71,26 @@ func (x Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Status.Descriptor instead.
 func (Status) EnumDescriptor() ([]byte, []int) {
-	return file_search_search_proto_rawDescGZIP(), []int{0}
+	return file_proto_search_search_proto_rawDescGZIP(), []int{0}
 }
 
 type SearchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Keywords      string                 `protobuf:"bytes,1,opt,name=keywords,proto3" json:"keywords,omitempty"`
-	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Keywords string                 `protobuf:"bytes,1,opt,name=keywords,proto3" json:"keywords,omitempty"`
+	Limit    int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// skip the best comics, can not be used with a cursor
+	Offset int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// next_cursor of the previous page
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// explain the score of every found comic
+	Explain       bool `protobuf:"varint,5,opt,name=explain,proto3" json:"explain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchRequest) Reset() {
 	*x = SearchRequest{}
-	mi := &file_search_search_proto_msgTypes[0]
+	mi := &file_proto_search_search_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -94,7 +102,7 @@ func (x *SearchRequest) String() string {
 func (*SearchRequest) ProtoMessage() {}
 
 func (x *SearchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_search_search_proto_msgTypes[0]
+	mi := &file_proto_search_search_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -107,7 +115,7 @@ func (x *SearchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
 func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return file_search_search_proto_rawDescGZIP(), []int{0}
+	return file_proto_search_search_proto_rawDescGZIP(), []int{0}
 }
 
 func (x *SearchRequest) GetKeywords() string {
@@ -124,16 +132,45 @@ func (x *SearchRequest) GetLimit() int64 {
 	return 0
 }
 
+func (x *SearchRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetExplain() bool {
+	if x != nil {
+		return x.Explain
+	}
+	return false
+}
+
 type StatusReply struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=search.Status" json:"status,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=search.Status" json:"status,omitempty"`
+	// end of the last finished index build
+	LastBuild  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_build,json=lastBuild,proto3" json:"last_build,omitempty"`
+	Duration   *durationpb.Duration   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Comics     int64                  `protobuf:"varint,4,opt,name=comics,proto3" json:"comics,omitempty"`
+	Vocabulary int64                  `protobuf:"varint,5,opt,name=vocabulary,proto3" json:"vocabulary,omitempty"`
+	// error of the last build, empty when it succeeded
+	LastError     string      `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	Cache         *CacheStats `protobuf:"bytes,7,opt,name=cache,proto3" json:"cache,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StatusReply) Reset() {
 	*x = StatusReply{}
-	mi := &file_search_search_proto_msgTypes[1]
+	mi := &file_proto_search_search_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -145,7 +182,7 @@ func (x *StatusReply) String() string {
 func (*StatusReply) ProtoMessage() {}
 
 func (x *StatusReply) ProtoReflect() protoreflect.Message {
-	mi := &file_search_search_proto_msgTypes[1]
+	mi := &file_proto_search_search_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -158,7 +195,7 @@ func (x *StatusReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusReply.ProtoReflect.Descriptor instead.
 func (*StatusReply) Descriptor() ([]byte, []int) {
-	return file_search_search_proto_rawDescGZIP(), []int{1}
+	return file_proto_search_search_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *StatusReply) GetStatus() Status {
@@ -168,17 +205,126 @@ func (x *StatusReply) GetStatus() Status {
 	return Status_STATUS_UNSPECIFIED
 }
 
+func (x *StatusReply) GetLastBuild() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastBuild
+	}
+	return nil
+}
+
+func (x *StatusReply) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *StatusReply) GetComics() int64 {
+	if x != nil {
+		return x.Comics
+	}
+	return 0
+}
+
+func (x *StatusReply) GetVocabulary() int64 {
+	if x != nil {
+		return x.Vocabulary
+	}
+	return 0
+}
+
+func (x *StatusReply) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *StatusReply) GetCache() *CacheStats {
+	if x != nil {
+		return x.Cache
+	}
+	return nil
+}
+
+// searches answered from the query cache
+type CacheStats struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Hits   int64                  `protobuf:"varint,1,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses int64                  `protobuf:"varint,2,opt,name=misses,proto3" json:"misses,omitempty"`
+	// number of cached results
+	Size          int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheStats) Reset() {
+	*x = CacheStats{}
+	mi := &file_proto_search_search_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStats) ProtoMessage() {}
+
+func (x *CacheStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStats.ProtoReflect.Descriptor instead.
+func (*CacheStats) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CacheStats) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheStats) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *CacheStats) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
 type Comics struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url   string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Score float64                `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	// piece of the comic text with found words in <b>, HTML
+	Snippet string `protobuf:"bytes,4,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	// set only if asked for
+	Explain       *Explanation `protobuf:"bytes,5,opt,name=explain,proto3" json:"explain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Comics) Reset() {
 	*x = Comics{}
-	mi := &file_search_search_proto_msgTypes[2]
+	mi := &file_proto_search_search_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -190,7 +336,7 @@ func (x *Comics) String() string {
 func (*Comics) ProtoMessage() {}
 
 func (x *Comics) ProtoReflect() protoreflect.Message {
-	mi := &file_search_search_proto_msgTypes[2]
+	mi := &file_proto_search_search_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -203,7 +349,7 @@ func (x *Comics) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Comics.ProtoReflect.Descriptor instead.
 func (*Comics) Descriptor() ([]byte, []int) {
-	return file_search_search_proto_rawDescGZIP(), []int{2}
+	return file_proto_search_search_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Comics) GetId() int64 {
@@ -220,16 +366,247 @@ func (x *Comics) GetUrl() string {
 	return ""
 }
 
-type SearchReply struct {
+func (x *Comics) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Comics) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+func (x *Comics) GetExplain() *Explanation {
+	if x != nil {
+		return x.Explain
+	}
+	return nil
+}
+
+// how the score of a found comic was made up
+type Explanation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// db:array, db:fts or index
+	Backend       string             `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"`
+	Terms         []*TermExplanation `protobuf:"bytes,2,rep,name=terms,proto3" json:"terms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Explanation) Reset() {
+	*x = Explanation{}
+	mi := &file_proto_search_search_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Explanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Explanation) ProtoMessage() {}
+
+func (x *Explanation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Explanation.ProtoReflect.Descriptor instead.
+func (*Explanation) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Explanation) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *Explanation) GetTerms() []*TermExplanation {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+// normalized query word, score is its BM25 score on its own times
+// the weight, 0 with full-text search
+type TermExplanation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Comics        []*Comics              `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	Term          string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	Matched       bool                   `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
+	TermFreq      int64                  `protobuf:"varint,3,opt,name=term_freq,json=termFreq,proto3" json:"term_freq,omitempty"`
+	DocFreq       int64                  `protobuf:"varint,4,opt,name=doc_freq,json=docFreq,proto3" json:"doc_freq,omitempty"`
+	Weight        float64                `protobuf:"fixed64,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	Score         float64                `protobuf:"fixed64,6,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TermExplanation) Reset() {
+	*x = TermExplanation{}
+	mi := &file_proto_search_search_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TermExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TermExplanation) ProtoMessage() {}
+
+func (x *TermExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TermExplanation.ProtoReflect.Descriptor instead.
+func (*TermExplanation) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TermExplanation) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *TermExplanation) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *TermExplanation) GetTermFreq() int64 {
+	if x != nil {
+		return x.TermFreq
+	}
+	return 0
+}
+
+func (x *TermExplanation) GetDocFreq() int64 {
+	if x != nil {
+		return x.DocFreq
+	}
+	return 0
+}
+
+func (x *TermExplanation) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *TermExplanation) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// unknown query word replaced with a similar indexed word
+type Expansion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Word          string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Term          string                 `protobuf:"bytes,2,opt,name=term,proto3" json:"term,omitempty"`
+	Distance      int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Expansion) Reset() {
+	*x = Expansion{}
+	mi := &file_proto_search_search_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Expansion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expansion) ProtoMessage() {}
+
+func (x *Expansion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expansion.ProtoReflect.Descriptor instead.
+func (*Expansion) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Expansion) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *Expansion) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *Expansion) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type SearchReply struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Comics []*Comics              `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	// normalized query as understood by the service
+	Query      string       `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Expansions []*Expansion `protobuf:"bytes,3,rep,name=expansions,proto3" json:"expansions,omitempty"`
+	// corrected query offered when nothing was found
+	Suggestion string `protobuf:"bytes,4,opt,name=suggestion,proto3" json:"suggestion,omitempty"`
+	// number of all found comics
+	Total int64 `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	// empty on the last page
+	NextCursor    string `protobuf:"bytes,6,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchReply) Reset() {
 	*x = SearchReply{}
-	mi := &file_search_search_proto_msgTypes[3]
+	mi := &file_proto_search_search_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -241,7 +618,7 @@ func (x *SearchReply) String() string {
 func (*SearchReply) ProtoMessage() {}
 
 func (x *SearchReply) ProtoReflect() protoreflect.Message {
-	mi := &file_search_search_proto_msgTypes[3]
+	mi := &file_proto_search_search_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -254,7 +631,7 @@ func (x *SearchReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchReply.ProtoReflect.Descriptor instead.
 func (*SearchReply) Descriptor() ([]byte, []int) {
-	return file_search_search_proto_rawDescGZIP(), []int{3}
+	return file_proto_search_search_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SearchReply) GetComics() []*Comics {
@@ -264,86 +641,821 @@ func (x *SearchReply) GetComics() []*Comics {
 	return nil
 }
 
-var File_search_search_proto protoreflect.FileDescriptor
+func (x *SearchReply) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
 
-const file_search_search_proto_rawDesc = "" +
-	"\n" +
-	"\x13search/search.proto\x12\x06search\x1a\x1bgoogle/protobuf/empty.proto\"A\n" +
-	"\rSearchRequest\x12\x1a\n" +
-	"\bkeywords\x18\x01 \x01(\tR\bkeywords\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x03R\x05limit\"5\n" +
-	"\vStatusReply\x12&\n" +
-	"\x06status\x18\x01 \x01(\x0e2\x0e.search.StatusR\x06status\"*\n" +
-	"\x06Comics\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
-	"\x03url\x18\x02 \x01(\tR\x03url\"5\n" +
-	"\vSearchReply\x12&\n" +
-	"\x06comics\x18\x01 \x03(\v2\x0e.search.ComicsR\x06comics*E\n" +
-	"\x06Status\x12\x16\n" +
-	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x0f\n" +
-	"\vSTATUS_IDLE\x10\x01\x12\x12\n" +
-	"\x0eSTATUS_RUNNING\x10\x022z\n" +
-	"\x06Search\x128\n" +
-	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x126\n" +
-	"\x06Search\x12\x15.search.SearchRequest\x1a\x13.search.SearchReply\"\x00B\x04Z\x02./b\x06proto3"
+func (x *SearchReply) GetExpansions() []*Expansion {
+	if x != nil {
+		return x.Expansions
+	}
+	return nil
+}
 
-var (
-	file_search_search_proto_rawDescOnce sync.Once
-	file_search_search_proto_rawDescData []byte
+func (x *SearchReply) GetSuggestion() string {
+	if x != nil {
+		return x.Suggestion
+	}
+	return ""
+}
+
+func (x *SearchReply) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchReply) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SuggestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestRequest) Reset() {
+	*x = SuggestRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestRequest) ProtoMessage() {}
+
+func (x *SuggestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestRequest.ProtoReflect.Descriptor instead.
+func (*SuggestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SuggestRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Completion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Word          string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	DocFreq       int64                  `protobuf:"varint,2,opt,name=doc_freq,json=docFreq,proto3" json:"doc_freq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Completion) Reset() {
+	*x = Completion{}
+	mi := &file_proto_search_search_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Completion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Completion) ProtoMessage() {}
+
+func (x *Completion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Completion.ProtoReflect.Descriptor instead.
+func (*Completion) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Completion) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *Completion) GetDocFreq() int64 {
+	if x != nil {
+		return x.DocFreq
+	}
+	return 0
+}
+
+type SuggestReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Completions   []*Completion          `protobuf:"bytes,1,rep,name=completions,proto3" json:"completions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestReply) Reset() {
+	*x = SuggestReply{}
+	mi := &file_proto_search_search_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestReply) ProtoMessage() {}
+
+func (x *SuggestReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestReply.ProtoReflect.Descriptor instead.
+func (*SuggestReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SuggestReply) GetCompletions() []*Completion {
+	if x != nil {
+		return x.Completions
+	}
+	return nil
+}
+
+// comics sharing the most distinctive words with the comic id
+type SimilarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarRequest) Reset() {
+	*x = SimilarRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarRequest) ProtoMessage() {}
+
+func (x *SimilarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarRequest.ProtoReflect.Descriptor instead.
+func (*SimilarRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SimilarRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SimilarRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SimilarReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comics        []*Comics              `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarReply) Reset() {
+	*x = SimilarReply{}
+	mi := &file_proto_search_search_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarReply) ProtoMessage() {}
+
+func (x *SimilarReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarReply.ProtoReflect.Descriptor instead.
+func (*SimilarReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SimilarReply) GetComics() []*Comics {
+	if x != nil {
+		return x.Comics
+	}
+	return nil
+}
+
+type GetComicRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetComicRequest) Reset() {
+	*x = GetComicRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetComicRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetComicRequest) ProtoMessage() {}
+
+func (x *GetComicRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetComicRequest.ProtoReflect.Descriptor instead.
+func (*GetComicRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetComicRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// comic with its texts, title, alt and transcript are empty and
+// published is not set for comics fetched before they were stored
+type ComicReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// image URL
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Alt           string                 `protobuf:"bytes,4,opt,name=alt,proto3" json:"alt,omitempty"`
+	Transcript    string                 `protobuf:"bytes,5,opt,name=transcript,proto3" json:"transcript,omitempty"`
+	Published     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=published,proto3" json:"published,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComicReply) Reset() {
+	*x = ComicReply{}
+	mi := &file_proto_search_search_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComicReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComicReply) ProtoMessage() {}
+
+func (x *ComicReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComicReply.ProtoReflect.Descriptor instead.
+func (*ComicReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ComicReply) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ComicReply) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ComicReply) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ComicReply) GetAlt() string {
+	if x != nil {
+		return x.Alt
+	}
+	return ""
+}
+
+func (x *ComicReply) GetTranscript() string {
+	if x != nil {
+		return x.Transcript
+	}
+	return ""
+}
+
+func (x *ComicReply) GetPublished() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Published
+	}
+	return nil
+}
+
+// picks among comics matching the phrase, among all comics if it is empty
+type RandomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phrase        string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RandomRequest) Reset() {
+	*x = RandomRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RandomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RandomRequest) ProtoMessage() {}
+
+func (x *RandomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RandomRequest.ProtoReflect.Descriptor instead.
+func (*RandomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RandomRequest) GetPhrase() string {
+	if x != nil {
+		return x.Phrase
+	}
+	return ""
+}
+
+// words searched in place of each other
+type SynonymGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Words         []string               `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynonymGroup) Reset() {
+	*x = SynonymGroup{}
+	mi := &file_proto_search_search_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynonymGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynonymGroup) ProtoMessage() {}
+
+func (x *SynonymGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynonymGroup.ProtoReflect.Descriptor instead.
+func (*SynonymGroup) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SynonymGroup) GetWords() []string {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+type SynonymsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*SynonymGroup        `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynonymsReply) Reset() {
+	*x = SynonymsReply{}
+	mi := &file_proto_search_search_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynonymsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynonymsReply) ProtoMessage() {}
+
+func (x *SynonymsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynonymsReply.ProtoReflect.Descriptor instead.
+func (*SynonymsReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SynonymsReply) GetGroups() []*SynonymGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type RemoveSynonymsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// any word of the group
+	Word          string `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveSynonymsRequest) Reset() {
+	*x = RemoveSynonymsRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveSynonymsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSynonymsRequest) ProtoMessage() {}
+
+func (x *RemoveSynonymsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSynonymsRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSynonymsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RemoveSynonymsRequest) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+var File_proto_search_search_proto protoreflect.FileDescriptor
+
+const file_proto_search_search_proto_rawDesc = "" +
+	"\n" +
+	"\x19proto/search/search.proto\x12\x06search\x1a\x1egoogle/protobuf/duration.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8b\x01\n" +
+	"\rSearchRequest\x12\x1a\n" +
+	"\bkeywords\x18\x01 \x01(\tR\bkeywords\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x18\n" +
+	"\aexplain\x18\x05 \x01(\bR\aexplain\"\xa8\x02\n" +
+	"\vStatusReply\x12&\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x0e.search.StatusR\x06status\x129\n" +
+	"\n" +
+	"last_build\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tlastBuild\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x16\n" +
+	"\x06comics\x18\x04 \x01(\x03R\x06comics\x12\x1e\n" +
+	"\n" +
+	"vocabulary\x18\x05 \x01(\x03R\n" +
+	"vocabulary\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x12(\n" +
+	"\x05cache\x18\a \x01(\v2\x12.search.CacheStatsR\x05cache\"L\n" +
+	"\n" +
+	"CacheStats\x12\x12\n" +
+	"\x04hits\x18\x01 \x01(\x03R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x02 \x01(\x03R\x06misses\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\"\x89\x01\n" +
+	"\x06Comics\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\x12\x18\n" +
+	"\asnippet\x18\x04 \x01(\tR\asnippet\x12-\n" +
+	"\aexplain\x18\x05 \x01(\v2\x13.search.ExplanationR\aexplain\"V\n" +
+	"\vExplanation\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12-\n" +
+	"\x05terms\x18\x02 \x03(\v2\x17.search.TermExplanationR\x05terms\"\xa5\x01\n" +
+	"\x0fTermExplanation\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x12\x18\n" +
+	"\amatched\x18\x02 \x01(\bR\amatched\x12\x1b\n" +
+	"\tterm_freq\x18\x03 \x01(\x03R\btermFreq\x12\x19\n" +
+	"\bdoc_freq\x18\x04 \x01(\x03R\adocFreq\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x01R\x06weight\x12\x14\n" +
+	"\x05score\x18\x06 \x01(\x01R\x05score\"O\n" +
+	"\tExpansion\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12\x12\n" +
+	"\x04term\x18\x02 \x01(\tR\x04term\x12\x1a\n" +
+	"\bdistance\x18\x03 \x01(\x05R\bdistance\"\xd5\x01\n" +
+	"\vSearchReply\x12&\n" +
+	"\x06comics\x18\x01 \x03(\v2\x0e.search.ComicsR\x06comics\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x121\n" +
+	"\n" +
+	"expansions\x18\x03 \x03(\v2\x11.search.ExpansionR\n" +
+	"expansions\x12\x1e\n" +
+	"\n" +
+	"suggestion\x18\x04 \x01(\tR\n" +
+	"suggestion\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\x12\x1f\n" +
+	"\vnext_cursor\x18\x06 \x01(\tR\n" +
+	"nextCursor\">\n" +
+	"\x0eSuggestRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\";\n" +
+	"\n" +
+	"Completion\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12\x19\n" +
+	"\bdoc_freq\x18\x02 \x01(\x03R\adocFreq\"D\n" +
+	"\fSuggestReply\x124\n" +
+	"\vcompletions\x18\x01 \x03(\v2\x12.search.CompletionR\vcompletions\"6\n" +
+	"\x0eSimilarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\"6\n" +
+	"\fSimilarReply\x12&\n" +
+	"\x06comics\x18\x01 \x03(\v2\x0e.search.ComicsR\x06comics\"!\n" +
+	"\x0fGetComicRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xb0\x01\n" +
+	"\n" +
+	"ComicReply\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x10\n" +
+	"\x03alt\x18\x04 \x01(\tR\x03alt\x12\x1e\n" +
+	"\n" +
+	"transcript\x18\x05 \x01(\tR\n" +
+	"transcript\x128\n" +
+	"\tpublished\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tpublished\"'\n" +
+	"\rRandomRequest\x12\x16\n" +
+	"\x06phrase\x18\x01 \x01(\tR\x06phrase\"$\n" +
+	"\fSynonymGroup\x12\x14\n" +
+	"\x05words\x18\x01 \x03(\tR\x05words\"=\n" +
+	"\rSynonymsReply\x12,\n" +
+	"\x06groups\x18\x01 \x03(\v2\x14.search.SynonymGroupR\x06groups\"+\n" +
+	"\x15RemoveSynonymsRequest\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word*E\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vSTATUS_IDLE\x10\x01\x12\x12\n" +
+	"\x0eSTATUS_RUNNING\x10\x022\x9d\a\n" +
+	"\x06Search\x128\n" +
+	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x126\n" +
+	"\x06Search\x12\x15.search.SearchRequest\x1a\x13.search.SearchReply\"\x00\x12;\n" +
+	"\vSearchIndex\x12\x15.search.SearchRequest\x1a\x13.search.SearchReply\"\x00\x12>\n" +
+	"\fSearchStream\x12\x15.search.SearchRequest\x1a\x13.search.SearchReply\"\x000\x01\x129\n" +
+	"\aSuggest\x12\x16.search.SuggestRequest\x1a\x14.search.SuggestReply\"\x00\x129\n" +
+	"\aSimilar\x12\x16.search.SimilarRequest\x1a\x14.search.SimilarReply\"\x00\x129\n" +
+	"\bGetComic\x12\x17.search.GetComicRequest\x1a\x12.search.ComicReply\"\x00\x125\n" +
+	"\x06Random\x12\x15.search.RandomRequest\x1a\x12.search.ComicReply\"\x00\x126\n" +
+	"\x06Latest\x12\x16.google.protobuf.Empty\x1a\x12.search.ComicReply\"\x00\x12<\n" +
+	"\vIndexStatus\x12\x16.google.protobuf.Empty\x1a\x13.search.StatusReply\"\x00\x12;\n" +
+	"\bSynonyms\x12\x16.google.protobuf.Empty\x1a\x15.search.SynonymsReply\"\x00\x12=\n" +
+	"\vAddSynonyms\x12\x14.search.SynonymGroup\x1a\x16.google.protobuf.Empty\"\x00\x12I\n" +
+	"\x0eRemoveSynonyms\x12\x1d.search.RemoveSynonymsRequest\x1a\x16.google.protobuf.Empty\"\x00\x12B\n" +
+	"\x0eReloadSynonyms\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x12;\n" +
+	"\aReindex\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00B\x1fZ\x1dyadro.com/course/proto/searchb\x06proto3"
+
+var (
+	file_proto_search_search_proto_rawDescOnce sync.Once
+	file_proto_search_search_proto_rawDescData []byte
 )
 
-func file_search_search_proto_rawDescGZIP() []byte {
-	file_search_search_proto_rawDescOnce.Do(func() {
-		file_search_search_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_search_search_proto_rawDesc), len(file_search_search_proto_rawDesc)))
+func file_proto_search_search_proto_rawDescGZIP() []byte {
+	file_proto_search_search_proto_rawDescOnce.Do(func() {
+		file_proto_search_search_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_search_search_proto_rawDesc), len(file_proto_search_search_proto_rawDesc)))
 	})
-	return file_search_search_proto_rawDescData
-}
-
-var file_search_search_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_search_search_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_search_search_proto_goTypes = []any{
-	(Status)(0),           // 0: search.Status
-	(*SearchRequest)(nil), // 1: search.SearchRequest
-	(*StatusReply)(nil),   // 2: search.StatusReply
-	(*Comics)(nil),        // 3: search.Comics
-	(*SearchReply)(nil),   // 4: search.SearchReply
-	(*emptypb.Empty)(nil), // 5: google.protobuf.Empty
-}
-var file_search_search_proto_depIdxs = []int32{
-	0, // 0: search.StatusReply.status:type_name -> search.Status
-	3, // 1: search.SearchReply.comics:type_name -> search.Comics
-	5, // 2: search.Search.Ping:input_type -> google.protobuf.Empty
-	1, // 3: search.Search.Search:input_type -> search.SearchRequest
-	5, // 4: search.Search.Ping:output_type -> google.protobuf.Empty
-	4, // 5: search.Search.Search:output_type -> search.SearchReply
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
-}
-
-func init() { file_search_search_proto_init() }
-func file_search_search_proto_init() {
-	if File_search_search_proto != nil {
+	return file_proto_search_search_proto_rawDescData
+}
+
+var file_proto_search_search_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_search_search_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_proto_search_search_proto_goTypes = []any{
+	(Status)(0),                   // 0: search.Status
+	(*SearchRequest)(nil),         // 1: search.SearchRequest
+	(*StatusReply)(nil),           // 2: search.StatusReply
+	(*CacheStats)(nil),            // 3: search.CacheStats
+	(*Comics)(nil),                // 4: search.Comics
+	(*Explanation)(nil),           // 5: search.Explanation
+	(*TermExplanation)(nil),       // 6: search.TermExplanation
+	(*Expansion)(nil),             // 7: search.Expansion
+	(*SearchReply)(nil),           // 8: search.SearchReply
+	(*SuggestRequest)(nil),        // 9: search.SuggestRequest
+	(*Completion)(nil),            // 10: search.Completion
+	(*SuggestReply)(nil),          // 11: search.SuggestReply
+	(*SimilarRequest)(nil),        // 12: search.SimilarRequest
+	(*SimilarReply)(nil),          // 13: search.SimilarReply
+	(*GetComicRequest)(nil),       // 14: search.GetComicRequest
+	(*ComicReply)(nil),            // 15: search.ComicReply
+	(*RandomRequest)(nil),         // 16: search.RandomRequest
+	(*SynonymGroup)(nil),          // 17: search.SynonymGroup
+	(*SynonymsReply)(nil),         // 18: search.SynonymsReply
+	(*RemoveSynonymsRequest)(nil), // 19: search.RemoveSynonymsRequest
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 21: google.protobuf.Duration
+	(*emptypb.Empty)(nil),         // 22: google.protobuf.Empty
+}
+var file_proto_search_search_proto_depIdxs = []int32{
+	0,  // 0: search.StatusReply.status:type_name -> search.Status
+	20, // 1: search.StatusReply.last_build:type_name -> google.protobuf.Timestamp
+	21, // 2: search.StatusReply.duration:type_name -> google.protobuf.Duration
+	3,  // 3: search.StatusReply.cache:type_name -> search.CacheStats
+	5,  // 4: search.Comics.explain:type_name -> search.Explanation
+	6,  // 5: search.Explanation.terms:type_name -> search.TermExplanation
+	4,  // 6: search.SearchReply.comics:type_name -> search.Comics
+	7,  // 7: search.SearchReply.expansions:type_name -> search.Expansion
+	10, // 8: search.SuggestReply.completions:type_name -> search.Completion
+	4,  // 9: search.SimilarReply.comics:type_name -> search.Comics
+	20, // 10: search.ComicReply.published:type_name -> google.protobuf.Timestamp
+	17, // 11: search.SynonymsReply.groups:type_name -> search.SynonymGroup
+	22, // 12: search.Search.Ping:input_type -> google.protobuf.Empty
+	1,  // 13: search.Search.Search:input_type -> search.SearchRequest
+	1,  // 14: search.Search.SearchIndex:input_type -> search.SearchRequest
+	1,  // 15: search.Search.SearchStream:input_type -> search.SearchRequest
+	9,  // 16: search.Search.Suggest:input_type -> search.SuggestRequest
+	12, // 17: search.Search.Similar:input_type -> search.SimilarRequest
+	14, // 18: search.Search.GetComic:input_type -> search.GetComicRequest
+	16, // 19: search.Search.Random:input_type -> search.RandomRequest
+	22, // 20: search.Search.Latest:input_type -> google.protobuf.Empty
+	22, // 21: search.Search.IndexStatus:input_type -> google.protobuf.Empty
+	22, // 22: search.Search.Synonyms:input_type -> google.protobuf.Empty
+	17, // 23: search.Search.AddSynonyms:input_type -> search.SynonymGroup
+	19, // 24: search.Search.RemoveSynonyms:input_type -> search.RemoveSynonymsRequest
+	22, // 25: search.Search.ReloadSynonyms:input_type -> google.protobuf.Empty
+	22, // 26: search.Search.Reindex:input_type -> google.protobuf.Empty
+	22, // 27: search.Search.Ping:output_type -> google.protobuf.Empty
+	8,  // 28: search.Search.Search:output_type -> search.SearchReply
+	8,  // 29: search.Search.SearchIndex:output_type -> search.SearchReply
+	8,  // 30: search.Search.SearchStream:output_type -> search.SearchReply
+	11, // 31: search.Search.Suggest:output_type -> search.SuggestReply
+	13, // 32: search.Search.Similar:output_type -> search.SimilarReply
+	15, // 33: search.Search.GetComic:output_type -> search.ComicReply
+	15, // 34: search.Search.Random:output_type -> search.ComicReply
+	15, // 35: search.Search.Latest:output_type -> search.ComicReply
+	2,  // 36: search.Search.IndexStatus:output_type -> search.StatusReply
+	18, // 37: search.Search.Synonyms:output_type -> search.SynonymsReply
+	22, // 38: search.Search.AddSynonyms:output_type -> google.protobuf.Empty
+	22, // 39: search.Search.RemoveSynonyms:output_type -> google.protobuf.Empty
+	22, // 40: search.Search.ReloadSynonyms:output_type -> google.protobuf.Empty
+	22, // 41: search.Search.Reindex:output_type -> google.protobuf.Empty
+	27, // [27:42] is the sub-list for method output_type
+	12, // [12:27] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_proto_search_search_proto_init() }
+func file_proto_search_search_proto_init() {
+	if File_proto_search_search_proto != nil {
 		return
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: unsafe.Slice(unsafe.StringData(file_search_search_proto_rawDesc), len(file_search_search_proto_rawDesc)),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_search_search_proto_rawDesc), len(file_proto_search_search_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   4,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
-		GoTypes:           file_search_search_proto_goTypes,
-		DependencyIndexes: file_search_search_proto_depIdxs,
-		EnumInfos:         file_search_search_proto_enumTypes,
-		MessageInfos:      file_search_search_proto_msgTypes,
+		GoTypes:           file_proto_search_search_proto_goTypes,
+		DependencyIndexes: file_proto_search_search_proto_depIdxs,
+		EnumInfos:         file_proto_search_search_proto_enumTypes,
+		MessageInfos:      file_proto_search_search_proto_msgTypes,
 	}.Build()
-	File_search_search_proto = out.File
-	file_search_search_proto_goTypes = nil
-	file_search_search_proto_depIdxs = nil
+	File_proto_search_search_proto = out.File
+	file_proto_search_search_proto_goTypes = nil
+	file_proto_search_search_proto_depIdxs = nil
 }
@@ -2,9 +2,9 @@
 // versions:
 // - protoc-gen-go-grpc v1.5.1
 // - protoc             v6.30.2
-// source: search/search.proto
+// source: proto/search/search.proto
 
-package __
+package search
 
 import (
 	context "context"
@@ -21,8 +21,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Search_Ping_FullMethodName   = "/search.Search/Ping"
-	Search_Search_FullMethodName = "/search.Search/Search"
+	Search_Ping_FullMethodName           = "/search.Search/Ping"
+	Search_Search_FullMethodName         = "/search.Search/Search"
+	Search_SearchIndex_FullMethodName    = "/search.Search/SearchIndex"
+	Search_SearchStream_FullMethodName   = "/search.Search/SearchStream"
+	Search_Suggest_FullMethodName        = "/search.Search/Suggest"
+	Search_Similar_FullMethodName        = "/search.Search/Similar"
+	Search_GetComic_FullMethodName       = "/search.Search/GetComic"
+	Search_Random_FullMethodName         = "/search.Search/Random"
+	Search_Latest_FullMethodName         = "/search.Search/Latest"
+	Search_IndexStatus_FullMethodName    = "/search.Search/IndexStatus"
+	Search_Synonyms_FullMethodName       = "/search.Search/Synonyms"
+	Search_AddSynonyms_FullMethodName    = "/search.Search/AddSynonyms"
+	Search_RemoveSynonyms_FullMethodName = "/search.Search/RemoveSynonyms"
+	Search_ReloadSynonyms_FullMethodName = "/search.Search/ReloadSynonyms"
+	Search_Reindex_FullMethodName        = "/search.Search/Reindex"
 )
 
 // SearchClient is the client API for Search service.
@@ -31,6 +44,24 @@ const (
 type SearchClient interface {
 	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchReply, error)
+	SearchIndex(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchReply, error)
+	// the first reply has no comics, the next ones carry the found comics
+	// in rank order, a few at a time
+	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchReply], error)
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestReply, error)
+	Similar(ctx context.Context, in *SimilarRequest, opts ...grpc.CallOption) (*SimilarReply, error)
+	GetComic(ctx context.Context, in *GetComicRequest, opts ...grpc.CallOption) (*ComicReply, error)
+	Random(ctx context.Context, in *RandomRequest, opts ...grpc.CallOption) (*ComicReply, error)
+	// the comic with the largest id
+	Latest(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ComicReply, error)
+	IndexStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatusReply, error)
+	Synonyms(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SynonymsReply, error)
+	AddSynonyms(ctx context.Context, in *SynonymGroup, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveSynonyms(ctx context.Context, in *RemoveSynonymsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// read the synonyms file again after it was edited by hand
+	ReloadSynonyms(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// refresh the index soon, called after comics are added or removed
+	Reindex(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type searchClient struct {
@@ -61,12 +92,169 @@ func (c *searchClient) Search(ctx context.Context, in *SearchRequest, opts ...gr
 	return out, nil
 }
 
+func (c *searchClient) SearchIndex(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchReply)
+	err := c.cc.Invoke(ctx, Search_SearchIndex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Search_ServiceDesc.Streams[0], Search_SearchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, SearchReply]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Search_SearchStreamClient = grpc.ServerStreamingClient[SearchReply]
+
+func (c *searchClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestReply)
+	err := c.cc.Invoke(ctx, Search_Suggest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) Similar(ctx context.Context, in *SimilarRequest, opts ...grpc.CallOption) (*SimilarReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimilarReply)
+	err := c.cc.Invoke(ctx, Search_Similar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) GetComic(ctx context.Context, in *GetComicRequest, opts ...grpc.CallOption) (*ComicReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComicReply)
+	err := c.cc.Invoke(ctx, Search_GetComic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) Random(ctx context.Context, in *RandomRequest, opts ...grpc.CallOption) (*ComicReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComicReply)
+	err := c.cc.Invoke(ctx, Search_Random_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) Latest(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ComicReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComicReply)
+	err := c.cc.Invoke(ctx, Search_Latest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) IndexStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatusReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusReply)
+	err := c.cc.Invoke(ctx, Search_IndexStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) Synonyms(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SynonymsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SynonymsReply)
+	err := c.cc.Invoke(ctx, Search_Synonyms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) AddSynonyms(ctx context.Context, in *SynonymGroup, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Search_AddSynonyms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) RemoveSynonyms(ctx context.Context, in *RemoveSynonymsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Search_RemoveSynonyms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) ReloadSynonyms(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Search_ReloadSynonyms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) Reindex(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Search_Reindex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SearchServer is the server API for Search service.
 // All implementations must embed UnimplementedSearchServer
 // for forward compatibility.
 type SearchServer interface {
 	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	Search(context.Context, *SearchRequest) (*SearchReply, error)
+	SearchIndex(context.Context, *SearchRequest) (*SearchReply, error)
+	// the first reply has no comics, the next ones carry the found comics
+	// in rank order, a few at a time
+	SearchStream(*SearchRequest, grpc.ServerStreamingServer[SearchReply]) error
+	Suggest(context.Context, *SuggestRequest) (*SuggestReply, error)
+	Similar(context.Context, *SimilarRequest) (*SimilarReply, error)
+	GetComic(context.Context, *GetComicRequest) (*ComicReply, error)
+	Random(context.Context, *RandomRequest) (*ComicReply, error)
+	// the comic with the largest id
+	Latest(context.Context, *emptypb.Empty) (*ComicReply, error)
+	IndexStatus(context.Context, *emptypb.Empty) (*StatusReply, error)
+	Synonyms(context.Context, *emptypb.Empty) (*SynonymsReply, error)
+	AddSynonyms(context.Context, *SynonymGroup) (*emptypb.Empty, error)
+	RemoveSynonyms(context.Context, *RemoveSynonymsRequest) (*emptypb.Empty, error)
+	// read the synonyms file again after it was edited by hand
+	ReloadSynonyms(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// refresh the index soon, called after comics are added or removed
+	Reindex(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	mustEmbedUnimplementedSearchServer()
 }
 
@@ -83,6 +271,45 @@ func (UnimplementedSearchServer) Ping(context.Context, *emptypb.Empty) (*emptypb
 func (UnimplementedSearchServer) Search(context.Context, *SearchRequest) (*SearchReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
+func (UnimplementedSearchServer) SearchIndex(context.Context, *SearchRequest) (*SearchReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchIndex not implemented")
+}
+func (UnimplementedSearchServer) SearchStream(*SearchRequest, grpc.ServerStreamingServer[SearchReply]) error {
+	return status.Errorf(codes.Unimplemented, "method SearchStream not implemented")
+}
+func (UnimplementedSearchServer) Suggest(context.Context, *SuggestRequest) (*SuggestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedSearchServer) Similar(context.Context, *SimilarRequest) (*SimilarReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Similar not implemented")
+}
+func (UnimplementedSearchServer) GetComic(context.Context, *GetComicRequest) (*ComicReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComic not implemented")
+}
+func (UnimplementedSearchServer) Random(context.Context, *RandomRequest) (*ComicReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Random not implemented")
+}
+func (UnimplementedSearchServer) Latest(context.Context, *emptypb.Empty) (*ComicReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Latest not implemented")
+}
+func (UnimplementedSearchServer) IndexStatus(context.Context, *emptypb.Empty) (*StatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IndexStatus not implemented")
+}
+func (UnimplementedSearchServer) Synonyms(context.Context, *emptypb.Empty) (*SynonymsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Synonyms not implemented")
+}
+func (UnimplementedSearchServer) AddSynonyms(context.Context, *SynonymGroup) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSynonyms not implemented")
+}
+func (UnimplementedSearchServer) RemoveSynonyms(context.Context, *RemoveSynonymsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSynonyms not implemented")
+}
+func (UnimplementedSearchServer) ReloadSynonyms(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadSynonyms not implemented")
+}
+func (UnimplementedSearchServer) Reindex(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reindex not implemented")
+}
 func (UnimplementedSearchServer) mustEmbedUnimplementedSearchServer() {}
 func (UnimplementedSearchServer) testEmbeddedByValue()                {}
 
@@ -140,6 +367,233 @@ func _Search_Search_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Search_SearchIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SearchIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_SearchIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SearchIndex(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SearchServer).SearchStream(m, &grpc.GenericServerStream[SearchRequest, SearchReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Search_SearchStreamServer = grpc.ServerStreamingServer[SearchReply]
+
+func _Search_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Suggest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_Similar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimilarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Similar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Similar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Similar(ctx, req.(*SimilarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_GetComic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetComicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).GetComic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_GetComic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).GetComic(ctx, req.(*GetComicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_Random_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RandomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Random(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Random_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Random(ctx, req.(*RandomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_Latest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Latest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Latest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Latest(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_IndexStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).IndexStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_IndexStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).IndexStatus(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_Synonyms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Synonyms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Synonyms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Synonyms(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_AddSynonyms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SynonymGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).AddSynonyms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_AddSynonyms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).AddSynonyms(ctx, req.(*SynonymGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_RemoveSynonyms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSynonymsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).RemoveSynonyms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_RemoveSynonyms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).RemoveSynonyms(ctx, req.(*RemoveSynonymsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_ReloadSynonyms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).ReloadSynonyms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_ReloadSynonyms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).ReloadSynonyms(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_Reindex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Reindex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Reindex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Reindex(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Search_ServiceDesc is the grpc.ServiceDesc for Search service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -155,7 +609,61 @@ var Search_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Search",
 			Handler:    _Search_Search_Handler,
 		},
+		{
+			MethodName: "SearchIndex",
+			Handler:    _Search_SearchIndex_Handler,
+		},
+		{
+			MethodName: "Suggest",
+			Handler:    _Search_Suggest_Handler,
+		},
+		{
+			MethodName: "Similar",
+			Handler:    _Search_Similar_Handler,
+		},
+		{
+			MethodName: "GetComic",
+			Handler:    _Search_GetComic_Handler,
+		},
+		{
+			MethodName: "Random",
+			Handler:    _Search_Random_Handler,
+		},
+		{
+			MethodName: "Latest",
+			Handler:    _Search_Latest_Handler,
+		},
+		{
+			MethodName: "IndexStatus",
+			Handler:    _Search_IndexStatus_Handler,
+		},
+		{
+			MethodName: "Synonyms",
+			Handler:    _Search_Synonyms_Handler,
+		},
+		{
+			MethodName: "AddSynonyms",
+			Handler:    _Search_AddSynonyms_Handler,
+		},
+		{
+			MethodName: "RemoveSynonyms",
+			Handler:    _Search_RemoveSynonyms_Handler,
+		},
+		{
+			MethodName: "ReloadSynonyms",
+			Handler:    _Search_ReloadSynonyms_Handler,
+		},
+		{
+			MethodName: "Reindex",
+			Handler:    _Search_Reindex_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchStream",
+			Handler:       _Search_SearchStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "search/search.proto",
+	Metadata: "proto/search/search.proto",
 }
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 
 	"github.com/lib/pq"
@@ -35,42 +37,232 @@ func (db *DB) CheckDB() error {
 	return err
 }
 
-func (db *DB) Search(ctx context.Context, keyword string) ([]int, error) {
-	var ids []int
+type Posting struct {
+	ID        int            `db:"id"`
+	Freq      int            `db:"freq"`
+	Len       int            `db:"len"`
+	Positions pq.Int64Array  `db:"positions"`
+	Fields    pq.StringArray `db:"fields"`
+}
+
+// comics fetched before positions were stored have no positions column,
+// array indexes are used instead, comics fetched before fields were stored
+// have no fields
+func (db *DB) Search(ctx context.Context, keyword string) ([]core.Posting, error) {
+	var postings []Posting
+
+	query := `SELECT id,
+       cardinality(array_positions(words, $1)) AS freq,
+       cardinality(words) AS len,
+       ARRAY(SELECT COALESCE(positions[i], i - 1)
+             FROM unnest(array_positions(words, $1)) AS i) AS positions,
+       CASE WHEN fields IS NULL THEN '{}'::text[]
+            ELSE ARRAY(SELECT COALESCE(fields[i], '')
+                       FROM unnest(array_positions(words, $1)) AS i) END AS fields
+    FROM comics WHERE $1 = ANY(words)`
+
+	err := db.conn.SelectContext(ctx, &postings, query, keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]core.Posting, 0, len(postings))
+	for _, p := range postings {
+		result = append(result, core.Posting{
+			ID:        p.ID,
+			Freq:      p.Freq,
+			Len:       p.Len,
+			Positions: toInts(p.Positions),
+			Fields:    toFields(p.Fields),
+		})
+	}
+	return result, nil
+
+}
+
+// FullText uses the GIN index on tsv, the query is given in tsquery syntax
+// and is not normalized again.
+func (db *DB) FullText(ctx context.Context, text core.TextQuery) ([]core.Comics, error) {
+	var rows []struct {
+		ID    int     `db:"id"`
+		Score float64 `db:"score"`
+	}
+
+	query := `SELECT id, ts_rank_cd($3::float4[], tsv, $2::tsquery) AS score
+    FROM comics WHERE tsv @@ $1::tsquery
+    ORDER BY score DESC, id`
+
+	err := db.conn.SelectContext(ctx, &rows, query, text.Match, text.Rank, pq.Array(text.Weights))
+	if err != nil {
+		return nil, err
+	}
+
+	comics := make([]core.Comics, 0, len(rows))
+	for _, r := range rows {
+		comics = append(comics, core.Comics{ID: r.ID, Score: r.Score})
+	}
+	return comics, nil
+}
+
+func (db *DB) Stats(ctx context.Context) (core.CorpusStats, error) {
+	var stats struct {
+		Docs   int     `db:"docs"`
+		AvgLen float64 `db:"avg_len"`
+	}
 
-	query := `SELECT id FROM comics WHERE $1 = ANY(words)`
+	query := `SELECT COUNT(*) AS docs,
+       COALESCE(AVG(cardinality(words)), 0) AS avg_len
+    FROM comics`
 
-	err := db.conn.Select(&ids, query, keyword)
+	err := db.conn.GetContext(ctx, &stats, query)
 	if err != nil {
-		return ids, err
+		return core.CorpusStats{}, err
 	}
-	return ids, nil
 
+	return core.CorpusStats{Docs: stats.Docs, AvgLen: stats.AvgLen}, nil
 }
 
 type Comics struct {
-	ID    int            `db:"id"`
-	URL   string         `db:"url"`
-	Words pq.StringArray `db:"words"`
+	ID        int            `db:"id"`
+	URL       string         `db:"url"`
+	Words     pq.StringArray `db:"words"`
+	Positions pq.Int64Array  `db:"positions"`
+	Fields    pq.StringArray `db:"fields"`
+	Version   int64          `db:"version"`
+	// texts are selected only to be shown with the comic
+	Title      string        `db:"title"`
+	Alt        string        `db:"alt"`
+	Transcript string        `db:"transcript"`
+	Starts     pq.Int64Array `db:"field_starts"`
+	Published  sql.NullTime  `db:"published"`
 }
 
+// comicsTexts selects texts of comic fields and where their words start,
+// comics fetched before texts were stored have none.
+const comicsTexts = `COALESCE(title, '') AS title, COALESCE(alt, '') AS alt,
+       COALESCE(transcript, '') AS transcript, field_starts`
+
 func (db *DB) Get(ctx context.Context, id int) (core.Comics, error) {
 	var comics Comics
 
-	query := `SELECT url,words FROM comics WHERE id=$1`
+	query := `SELECT url,words,positions,fields,version,published,` + comicsTexts + ` FROM comics WHERE id=$1`
 
-	err := db.conn.Get(&comics, query, id)
+	err := db.conn.GetContext(ctx, &comics, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.Comics{}, core.ErrNotFound
+	}
 	if err != nil {
 		return core.Comics{}, err
 	}
 
-	return core.Comics{ID: id, URL: comics.URL, Words: comics.Words}, err
+	comics.ID = id
+	return comics.toCore(), err
+
+}
+
+func (db *DB) GetMany(ctx context.Context, ids []int) ([]core.Comics, error) {
+	var rows []Comics
+
+	query := `SELECT id,url,words,positions,fields,version,` + comicsTexts + `
+    FROM comics WHERE id = ANY($1)`
+
+	err := db.conn.SelectContext(ctx, &rows, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	comics := make([]core.Comics, 0, len(rows))
+	for _, c := range rows {
+		comics = append(comics, c.toCore())
+	}
+	return comics, nil
+}
+
+// Updated reads rows as they arrive instead of loading the whole result.
+func (db *DB) Updated(ctx context.Context, version int64, fn func(core.Comics) error) error {
+	query := `SELECT id,url,words,positions,fields,version,` + comicsTexts + `
+    FROM comics WHERE version > $1 ORDER BY version`
+
+	rows, err := db.conn.QueryxContext(ctx, query, version)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c Comics
+		if err := rows.StructScan(&c); err != nil {
+			return err
+		}
+		if err := fn(c.toCore()); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (c Comics) toCore() core.Comics {
+	return core.Comics{
+		ID:          c.ID,
+		URL:         c.URL,
+		Title:       c.Title,
+		Alt:         c.Alt,
+		Transcript:  c.Transcript,
+		FieldStarts: toInts(c.Starts),
+		Published:   c.Published.Time,
+		Tokens:      toTokens(c.Words, c.Positions, c.Fields),
+		Version:     c.Version,
+	}
+}
+
+func toTokens(words []string, positions []int64, fields []string) []core.Token {
+	tokens := make([]core.Token, 0, len(words))
+	for i, word := range words {
+		token := core.Token{Word: word, Position: i}
+		if i < len(positions) {
+			token.Position = int(positions[i])
+		}
+		if i < len(fields) {
+			token.Field = core.Field(fields[i])
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func toFields(values []string) []core.Field {
+	if len(values) == 0 {
+		return nil
+	}
+	fields := make([]core.Field, 0, len(values))
+	for _, v := range values {
+		fields = append(fields, core.Field(v))
+	}
+	return fields
+}
 
+func toInts(values []int64) []int {
+	ints := make([]int, 0, len(values))
+	for _, v := range values {
+		ints = append(ints, int(v))
+	}
+	return ints
+}
+
+func (db *DB) RandomID(ctx context.Context) (int, error) {
+	var id int
+	query := `SELECT id FROM comics ORDER BY random() LIMIT 1`
+
+	err := db.conn.GetContext(ctx, &id, query)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, core.ErrNotFound
+	}
+	return id, err
 }
 
 func (db *DB) MaxId(ctx context.Context) (int, error) {
 	var id int
-	query := `SELECT MAX(id) FROM comics`
+	query := `SELECT COALESCE(MAX(id), 0) FROM comics`
 
 	err := db.conn.Get(&id, query)
 
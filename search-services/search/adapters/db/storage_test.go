@@ -0,0 +1,43 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/search/core"
+)
+
+func TestToCoreTokens(t *testing.T) {
+	var row Comics
+	// arrays as Postgres returns them for the comics row
+	require.NoError(t, row.Words.Scan([]byte(`{tabl,bobbi,tabl}`)))
+	require.NoError(t, row.Positions.Scan([]byte(`{2,5,11}`)))
+	require.NoError(t, row.Fields.Scan([]byte(`{title,alt,alt}`)))
+
+	assert.Equal(t, []core.Token{
+		{Word: "tabl", Position: 2, Field: core.FieldTitle},
+		{Word: "bobbi", Position: 5, Field: core.FieldAlt},
+		{Word: "tabl", Position: 11, Field: core.FieldAlt},
+	}, row.toCore().Tokens)
+}
+
+func TestToCoreTokensWithoutPositions(t *testing.T) {
+	// comics stored before positions and fields get word indexes
+	// as positions and no field
+	row := Comics{Words: []string{"tabl", "bobbi"}}
+	assert.Equal(t, []core.Token{
+		{Word: "tabl", Position: 0},
+		{Word: "bobbi", Position: 1},
+	}, row.toCore().Tokens)
+}
+
+func TestToCoreFieldStarts(t *testing.T) {
+	var row Comics
+	require.NoError(t, row.Starts.Scan([]byte(`{0,117,305}`)))
+	assert.Equal(t, []int{0, 117, 305}, row.toCore().FieldStarts)
+
+	// comics fetched before texts were stored have no starts
+	require.NoError(t, row.Starts.Scan(nil))
+	assert.Empty(t, row.toCore().FieldStarts)
+}
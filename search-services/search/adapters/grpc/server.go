@@ -6,18 +6,26 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	seachpb "yadro.com/course/proto/search"
 	"yadro.com/course/search/core"
 )
 
-func NewServer(service core.Searcher) *Server {
-	return &Server{service: service}
+// Reindexer schedules an index build.
+type Reindexer interface {
+	Reindex()
+}
+
+func NewServer(service core.Searcher, reindexer Reindexer) *Server {
+	return &Server{service: service, reindexer: reindexer}
 }
 
 type Server struct {
 	seachpb.UnimplementedSearchServer
-	service core.Searcher
+	service   core.Searcher
+	reindexer Reindexer
 }
 
 func (s *Server) Ping(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
@@ -25,37 +33,241 @@ func (s *Server) Ping(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, erro
 }
 
 func (s *Server) Search(ctx context.Context, in *seachpb.SearchRequest) (*seachpb.SearchReply, error) {
-	searchQuery := core.SearchQuery{Keywords: in.Keywords, Limit: int(in.Limit)}
+	searchQuery := searchQuery(in)
 	replay, err := s.service.Search(ctx, searchQuery)
 	if err != nil {
-		if errors.Is(err, core.ErrNotFound) {
-			return nil, status.Error(codes.NotFound, "nothing found")
-		}
+		return nil, searchError(err)
 	}
 
-	comics := make([]*seachpb.Comics, 0)
-	for _, index := range replay {
-		comics = append(comics, &seachpb.Comics{Id: int64(index.ID), Url: index.URL})
-	}
-
-	return &seachpb.SearchReply{Comics: comics}, err
+	return searchReply(replay), nil
 
 }
 
 func (s *Server) SearchIndex(ctx context.Context, in *seachpb.SearchRequest) (*seachpb.SearchReply, error) {
-	searchQuery := core.SearchQuery{Keywords: in.Keywords, Limit: int(in.Limit)}
+	searchQuery := searchQuery(in)
 	replay, err := s.service.SearchIndex(ctx, searchQuery)
 	if err != nil {
-		if errors.Is(err, core.ErrNotFound) {
-			return nil, status.Error(codes.NotFound, "nothing found")
-		}
+		return nil, searchError(err)
+	}
+
+	return searchReply(replay), nil
+
+}
+
+// SearchStream sends the result without comics first and then the found
+// comics in rank order, a few at a time.
+func (s *Server) SearchStream(in *seachpb.SearchRequest, stream seachpb.Search_SearchStreamServer) error {
+	searchQuery := searchQuery(in)
+	err := s.service.SearchStream(stream.Context(), searchQuery, func(result core.SearchResult) error {
+		return stream.Send(searchReply(result))
+	})
+	if err != nil {
+		return searchError(err)
 	}
+	return nil
+}
 
-	comics := make([]*seachpb.Comics, 0)
-	for _, index := range replay {
-		comics = append(comics, &seachpb.Comics{Id: int64(index.ID), Url: index.URL})
+func (s *Server) Suggest(ctx context.Context, in *seachpb.SuggestRequest) (*seachpb.SuggestReply, error) {
+	completions, err := s.service.Suggest(ctx, in.Prefix, int(in.Limit))
+	if err != nil {
+		return nil, searchError(err)
 	}
 
-	return &seachpb.SearchReply{Comics: comics}, err
+	reply := &seachpb.SuggestReply{Completions: make([]*seachpb.Completion, 0, len(completions))}
+	for _, c := range completions {
+		reply.Completions = append(reply.Completions, &seachpb.Completion{Word: c.Word, DocFreq: int64(c.DocFreq)})
+	}
+	return reply, nil
+}
+
+func (s *Server) Similar(ctx context.Context, in *seachpb.SimilarRequest) (*seachpb.SimilarReply, error) {
+	comics, err := s.service.Similar(ctx, int(in.Id), int(in.Limit))
+	if err != nil {
+		return nil, searchError(err)
+	}
+	return &seachpb.SimilarReply{Comics: comicsReply(comics)}, nil
+}
 
+func (s *Server) GetComic(ctx context.Context, in *seachpb.GetComicRequest) (*seachpb.ComicReply, error) {
+	comics, err := s.service.GetComic(ctx, int(in.Id))
+	if err != nil {
+		return nil, searchError(err)
+	}
+	return comicReply(comics), nil
+}
+
+func (s *Server) Random(ctx context.Context, in *seachpb.RandomRequest) (*seachpb.ComicReply, error) {
+	comics, err := s.service.Random(ctx, in.Phrase)
+	if err != nil {
+		return nil, searchError(err)
+	}
+	return comicReply(comics), nil
+}
+
+func (s *Server) Latest(ctx context.Context, _ *emptypb.Empty) (*seachpb.ComicReply, error) {
+	comics, err := s.service.Latest(ctx)
+	if err != nil {
+		return nil, searchError(err)
+	}
+	return comicReply(comics), nil
+}
+
+func comicReply(comics core.Comics) *seachpb.ComicReply {
+	reply := &seachpb.ComicReply{
+		Id:         int64(comics.ID),
+		Url:        comics.URL,
+		Title:      comics.Title,
+		Alt:        comics.Alt,
+		Transcript: comics.Transcript,
+	}
+	if !comics.Published.IsZero() {
+		reply.Published = timestamppb.New(comics.Published)
+	}
+	return reply
+}
+
+func (s *Server) IndexStatus(ctx context.Context, _ *emptypb.Empty) (*seachpb.StatusReply, error) {
+	indexStatus, err := s.service.IndexStatus(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	reply := &seachpb.StatusReply{
+		Status:     seachpb.Status_STATUS_IDLE,
+		Duration:   durationpb.New(indexStatus.Duration),
+		Comics:     int64(indexStatus.Comics),
+		Vocabulary: int64(indexStatus.Vocabulary),
+		LastError:  indexStatus.LastError,
+		Cache: &seachpb.CacheStats{
+			Hits:   int64(indexStatus.Cache.Hits),
+			Misses: int64(indexStatus.Cache.Misses),
+			Size:   int64(indexStatus.Cache.Size),
+		},
+	}
+	if indexStatus.Status == core.StatusRunning {
+		reply.Status = seachpb.Status_STATUS_RUNNING
+	}
+	if !indexStatus.LastBuild.IsZero() {
+		reply.LastBuild = timestamppb.New(indexStatus.LastBuild)
+	}
+	return reply, nil
+}
+
+// Reindex drops cached results at once, comics may be gone already
+// while the index is still being built.
+func (s *Server) Reindex(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	s.service.ResetCache()
+	s.reindexer.Reindex()
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) Synonyms(ctx context.Context, _ *emptypb.Empty) (*seachpb.SynonymsReply, error) {
+	groups, err := s.service.Synonyms(ctx)
+	if err != nil {
+		return nil, searchError(err)
+	}
+
+	reply := &seachpb.SynonymsReply{Groups: make([]*seachpb.SynonymGroup, 0, len(groups))}
+	for _, g := range groups {
+		reply.Groups = append(reply.Groups, &seachpb.SynonymGroup{Words: g.Words})
+	}
+	return reply, nil
+}
+
+func (s *Server) AddSynonyms(ctx context.Context, in *seachpb.SynonymGroup) (*emptypb.Empty, error) {
+	if err := s.service.AddSynonyms(ctx, core.SynonymGroup{Words: in.Words}); err != nil {
+		return nil, searchError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) RemoveSynonyms(ctx context.Context, in *seachpb.RemoveSynonymsRequest) (*emptypb.Empty, error) {
+	if err := s.service.RemoveSynonyms(ctx, in.Word); err != nil {
+		return nil, searchError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ReloadSynonyms(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.service.LoadSynonyms(ctx); err != nil {
+		return nil, searchError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func searchError(err error) error {
+	switch {
+	case errors.Is(err, core.ErrNotFound):
+		return status.Error(codes.NotFound, "nothing found")
+	case errors.Is(err, core.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, core.ErrBadArguments):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+func searchQuery(in *seachpb.SearchRequest) core.SearchQuery {
+	return core.SearchQuery{
+		Keywords: in.Keywords,
+		Limit:    int(in.Limit),
+		Offset:   int(in.Offset),
+		Cursor:   in.Cursor,
+		Explain:  in.Explain,
+	}
+}
+
+func comicsReply(found []core.Comics) []*seachpb.Comics {
+	comics := make([]*seachpb.Comics, 0, len(found))
+	for _, index := range found {
+		comics = append(comics, &seachpb.Comics{
+			Id:      int64(index.ID),
+			Url:     index.URL,
+			Score:   index.Score,
+			Snippet: index.Snippet,
+			Explain: explanationReply(index.Explain),
+		})
+	}
+	return comics
+}
+
+func explanationReply(explain *core.Explanation) *seachpb.Explanation {
+	if explain == nil {
+		return nil
+	}
+	reply := &seachpb.Explanation{
+		Backend: explain.Backend,
+		Terms:   make([]*seachpb.TermExplanation, 0, len(explain.Terms)),
+	}
+	for _, t := range explain.Terms {
+		reply.Terms = append(reply.Terms, &seachpb.TermExplanation{
+			Term:     t.Term,
+			Matched:  t.Matched,
+			TermFreq: int64(t.TermFreq),
+			DocFreq:  int64(t.DocFreq),
+			Weight:   t.Weight,
+			Score:    t.Score,
+		})
+	}
+	return reply
+}
+
+func searchReply(result core.SearchResult) *seachpb.SearchReply {
+	comics := comicsReply(result.Comics)
+
+	expansions := make([]*seachpb.Expansion, 0, len(result.Expansions))
+	for _, e := range result.Expansions {
+		expansions = append(expansions, &seachpb.Expansion{Word: e.Word, Term: e.Term, Distance: int32(e.Distance)})
+	}
+
+	return &seachpb.SearchReply{
+		Comics:     comics,
+		Query:      result.Query,
+		Expansions: expansions,
+		Suggestion: result.Suggestion,
+		Total:      int64(result.Total),
+		NextCursor: result.NextCursor,
+	}
 }
@@ -8,26 +8,37 @@ import (
 	"yadro.com/course/search/core"
 )
 
+// TickerInitiator builds the index when asked to by Reindex,
+// the ticker refreshes it in case a notification was lost.
 type TickerInitiator struct {
-	ttl    time.Duration
-	svc    *core.Service
-	stopCh chan struct{}
+	ttl     time.Duration
+	svc     *core.Service
+	stopCh  chan struct{}
+	trigger chan struct{}
 }
 
 func NewTickerInitiator(svc *core.Service, ttl time.Duration) *TickerInitiator {
 	return &TickerInitiator{
-		ttl:    ttl,
-		svc:    svc,
-		stopCh: make(chan struct{}),
+		ttl:     ttl,
+		svc:     svc,
+		stopCh:  make(chan struct{}),
+		trigger: make(chan struct{}, 1),
 	}
 }
 
-func (i *TickerInitiator) Start(ctx context.Context, log *slog.Logger) {
-	err := i.svc.BuildIndex(ctx)
-	if err != nil {
-		log.Error("failed indexing", "error", err)
+// Reindex schedules an index build, requests coming while one is
+// pending are merged into it.
+func (i *TickerInitiator) Reindex() {
+	select {
+	case i.trigger <- struct{}{}:
+	default:
 	}
+}
 
+// Start builds the index on every tick and Reindex call, the first build
+// is up to the caller since the index may be restored from a snapshot.
+func (i *TickerInitiator) Start(ctx context.Context, log *slog.Logger) {
+	var err error
 	ticker := time.NewTicker(i.ttl)
 	for {
 		select {
@@ -36,6 +47,13 @@ func (i *TickerInitiator) Start(ctx context.Context, log *slog.Logger) {
 			if err != nil {
 				log.Error("failed indexing", "error", err)
 			}
+		case <-i.trigger:
+			log.Debug("reindexing on request")
+			err = i.svc.BuildIndex(ctx)
+			if err != nil {
+				log.Error("failed indexing", "error", err)
+			}
+			ticker.Reset(i.ttl)
 		case <-i.stopCh:
 			ticker.Stop()
 			return
@@ -0,0 +1,220 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"yadro.com/course/search/core"
+)
+
+// File layout, integers are unsigned varints unless noted:
+//
+//	magic "XKIX", format version (uint16, big endian)
+//	index version
+//	number of words, then every word as length and bytes
+//	number of comics, then every comic as ID, number of tokens
+//	and every token as word number, position and field, number of forms
+//	and every form as word number, length and bytes
+//	CRC-32 (IEEE) of everything above (uint32, big endian)
+//
+// Fields are written as 0 for none and as the number in core.Fields
+// plus one otherwise.
+const (
+	magic         = "XKIX"
+	formatVersion = 3
+	headerSize    = len(magic) + 2
+	checksumSize  = 4
+)
+
+var ErrCorrupted = errors.New("index snapshot is corrupted")
+
+type File struct {
+	path string
+}
+
+func New(path string) *File {
+	return &File{path: path}
+}
+
+// Save writes the snapshot next to the old one and renames it over,
+// so a crash never leaves a half written file behind.
+func (f *File) Save(snapshot core.Snapshot) error {
+	data := encode(snapshot)
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *File) Load() (core.Snapshot, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return core.Snapshot{}, core.ErrNotFound
+	}
+	if err != nil {
+		return core.Snapshot{}, err
+	}
+	return decode(data)
+}
+
+func encode(snapshot core.Snapshot) []byte {
+	words := make(map[string]uint64)
+	var vocabulary []string
+	addWord := func(word string) {
+		if _, ok := words[word]; !ok {
+			words[word] = uint64(len(vocabulary))
+			vocabulary = append(vocabulary, word)
+		}
+	}
+	for _, c := range snapshot.Comics {
+		for _, t := range c.Tokens {
+			addWord(t.Word)
+		}
+		for _, word := range slices.Sorted(maps.Keys(c.Forms)) {
+			addWord(word)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	buf.Write(binary.BigEndian.AppendUint16(nil, formatVersion))
+
+	putUvarint(&buf, uint64(snapshot.Version))
+	putUvarint(&buf, uint64(len(vocabulary)))
+	for _, word := range vocabulary {
+		putUvarint(&buf, uint64(len(word)))
+		buf.WriteString(word)
+	}
+	putUvarint(&buf, uint64(len(snapshot.Comics)))
+	for _, c := range snapshot.Comics {
+		putUvarint(&buf, uint64(c.ID))
+		putUvarint(&buf, uint64(len(c.Tokens)))
+		for _, t := range c.Tokens {
+			putUvarint(&buf, words[t.Word])
+			putUvarint(&buf, uint64(t.Position))
+			putUvarint(&buf, uint64(slices.Index(core.Fields, t.Field)+1))
+		}
+		putUvarint(&buf, uint64(len(c.Forms)))
+		for _, word := range slices.Sorted(maps.Keys(c.Forms)) {
+			putUvarint(&buf, words[word])
+			putUvarint(&buf, uint64(len(c.Forms[word])))
+			buf.WriteString(c.Forms[word])
+		}
+	}
+
+	return binary.BigEndian.AppendUint32(buf.Bytes(), crc32.ChecksumIEEE(buf.Bytes()))
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func decode(data []byte) (core.Snapshot, error) {
+	if len(data) < headerSize+checksumSize || string(data[:len(magic)]) != magic {
+		return core.Snapshot{}, ErrCorrupted
+	}
+	if v := binary.BigEndian.Uint16(data[len(magic):headerSize]); v != formatVersion {
+		return core.Snapshot{}, fmt.Errorf("unsupported index snapshot format %d", v)
+	}
+	body, checksum := data[:len(data)-checksumSize], data[len(data)-checksumSize:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(checksum) {
+		return core.Snapshot{}, ErrCorrupted
+	}
+
+	r := reader{data: body[headerSize:]}
+	snapshot := core.Snapshot{Version: int64(r.uvarint())}
+
+	vocabulary := make([]string, r.count())
+	for i := range vocabulary {
+		vocabulary[i] = r.string()
+	}
+
+	snapshot.Comics = make([]core.Comics, r.count())
+	for i := range snapshot.Comics {
+		c := core.Comics{ID: int(r.uvarint())}
+		c.Tokens = make([]core.Token, r.count())
+		for j := range c.Tokens {
+			word := r.uvarint()
+			if word >= uint64(len(vocabulary)) {
+				return core.Snapshot{}, ErrCorrupted
+			}
+			c.Tokens[j] = core.Token{Word: vocabulary[word], Position: int(r.uvarint())}
+			switch field := r.uvarint(); {
+			case field > uint64(len(core.Fields)):
+				return core.Snapshot{}, ErrCorrupted
+			case field > 0:
+				c.Tokens[j].Field = core.Fields[field-1]
+			}
+		}
+		if n := r.count(); n > 0 {
+			c.Forms = make(map[string]string, n)
+			for range n {
+				word := r.uvarint()
+				if word >= uint64(len(vocabulary)) {
+					return core.Snapshot{}, ErrCorrupted
+				}
+				c.Forms[vocabulary[word]] = r.string()
+			}
+		}
+		snapshot.Comics[i] = c
+	}
+
+	if r.err != nil || len(r.data) != 0 {
+		return core.Snapshot{}, ErrCorrupted
+	}
+	return snapshot, nil
+}
+
+// reader decodes varints and remembers the first error,
+// so that decode checks it only once at the end.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = ErrCorrupted
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+// count reads a number of items, it can not exceed the bytes left
+// since every item takes at least one byte.
+func (r *reader) count() int {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.err = ErrCorrupted
+		return 0
+	}
+	return int(n)
+}
+
+func (r *reader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.data[:n])
+	r.data = r.data[n:]
+	return s
+}
@@ -0,0 +1,58 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/search/core"
+)
+
+func TestSaveLoad(t *testing.T) {
+	file := New(filepath.Join(t.TempDir(), "index", "snapshot"))
+
+	_, err := file.Load()
+	assert.ErrorIs(t, err, core.ErrNotFound)
+
+	snapshot := core.Snapshot{
+		Version: 42,
+		Comics: []core.Comics{
+			{
+				ID:     1,
+				Tokens: []core.Token{{Word: "little", Position: 0}, {Word: "bobbi", Position: 1}, {Word: "tabl", Position: 2}},
+				Forms:  map[string]string{"bobbi": "bobby", "tabl": "tables"},
+			},
+			{ID: 2, Tokens: []core.Token{{Word: "exploit", Position: 0, Field: core.FieldTitle}, {Word: "mom", Position: 101, Field: core.FieldAlt}}},
+			{ID: 300, Tokens: []core.Token{{Word: "tabl", Position: 3}}},
+			{ID: 301, Tokens: []core.Token{}},
+		},
+	}
+	require.NoError(t, file.Save(snapshot))
+
+	loaded, err := file.Load()
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+}
+
+func TestLoadCorrupted(t *testing.T) {
+	file := New(filepath.Join(t.TempDir(), "snapshot"))
+	require.NoError(t, file.Save(core.Snapshot{
+		Version: 1,
+		Comics:  []core.Comics{{ID: 1, Tokens: []core.Token{{Word: "cat", Position: 0}}}},
+	}))
+
+	data, err := os.ReadFile(file.path)
+	require.NoError(t, err)
+
+	flipped := append([]byte(nil), data...)
+	flipped[len(flipped)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(file.path, flipped, 0o644))
+	_, err = file.Load()
+	assert.ErrorIs(t, err, ErrCorrupted)
+
+	require.NoError(t, os.WriteFile(file.path, data[:len(data)-1], 0o644))
+	_, err = file.Load()
+	assert.ErrorIs(t, err, ErrCorrupted)
+}
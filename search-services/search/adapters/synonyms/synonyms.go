@@ -0,0 +1,119 @@
+package synonyms
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"yadro.com/course/search/core"
+)
+
+// File keeps one group per line with words separated by commas,
+// empty lines and lines starting with # are skipped on load and kept
+// on save:
+//
+//	# vehicles
+//	car, automobile, auto
+type File struct {
+	path string
+}
+
+func New(path string) *File {
+	return &File{path: path}
+}
+
+// Save writes the groups next to the old file and renames it over,
+// so a crash never leaves a half written file behind. Comments, empty
+// lines and groups left are kept as they were written, new groups go
+// to the end.
+func (f *File) Save(groups []core.SynonymGroup) error {
+	old, err := os.ReadFile(f.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// unwritten counts groups not written yet by their words
+	unwritten := make(map[string]int)
+	for _, group := range groups {
+		unwritten[groupKey(group)]++
+	}
+
+	var b bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(old))
+	for scanner.Scan() {
+		if group, ok := parseLine(scanner.Text()); ok {
+			key := groupKey(group)
+			if unwritten[key] == 0 {
+				continue
+			}
+			unwritten[key]--
+		}
+		b.WriteString(scanner.Text())
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	for _, group := range groups {
+		key := groupKey(group)
+		if unwritten[key] == 0 {
+			continue
+		}
+		unwritten[key]--
+		b.WriteString(strings.Join(group.Words, ", "))
+		b.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, b.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *File) Load() ([]core.SynonymGroup, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, core.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []core.SynonymGroup
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if group, ok := parseLine(scanner.Text()); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups, scanner.Err()
+}
+
+// parseLine reads a group from a line, comments and lines with less than
+// two words are not groups.
+func parseLine(line string) (core.SynonymGroup, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return core.SynonymGroup{}, false
+	}
+	var group core.SynonymGroup
+	for _, word := range strings.Split(line, ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			group.Words = append(group.Words, word)
+		}
+	}
+	return group, len(group.Words) > 1
+}
+
+// groupKey tells groups apart by their words.
+func groupKey(group core.SynonymGroup) string {
+	return strings.Join(group.Words, ",")
+}
@@ -0,0 +1,61 @@
+package synonyms
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/search/core"
+)
+
+func TestSaveLoad(t *testing.T) {
+	file := New(filepath.Join(t.TempDir(), "data", "synonyms.txt"))
+
+	_, err := file.Load()
+	assert.ErrorIs(t, err, core.ErrNotFound)
+
+	groups := []core.SynonymGroup{
+		{Words: []string{"car", "automobile", "auto"}},
+		{Words: []string{"linux", "gnu"}},
+	}
+	require.NoError(t, file.Save(groups))
+
+	loaded, err := file.Load()
+	require.NoError(t, err)
+	assert.Equal(t, groups, loaded)
+}
+
+func TestLoadEdited(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.txt")
+	text := "# vehicles\n Car ,Automobile,\n\nlonely\nlinux, gnu\n"
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o644))
+
+	loaded, err := New(path).Load()
+	require.NoError(t, err)
+	assert.Equal(t, []core.SynonymGroup{
+		{Words: []string{"car", "automobile"}},
+		{Words: []string{"linux", "gnu"}},
+	}, loaded)
+}
+
+func TestSaveKeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.txt")
+	text := "# vehicles\n Car ,Automobile,\n\n# systems\nlinux, gnu\nlonely\n"
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o644))
+	file := New(path)
+
+	groups, err := file.Load()
+	require.NoError(t, err)
+	groups = append(groups[1:], core.SynonymGroup{Words: []string{"cat", "kitty"}})
+	require.NoError(t, file.Save(groups))
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# vehicles\n\n# systems\nlinux, gnu\nlonely\ncat, kitty\n", string(saved))
+
+	loaded, err := file.Load()
+	require.NoError(t, err)
+	assert.Equal(t, groups, loaded)
+}
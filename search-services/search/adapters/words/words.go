@@ -31,7 +31,7 @@ func NewClient(address string, log *slog.Logger) (*Client, error) {
 	}, nil
 }
 
-func (c Client) Norm(ctx context.Context, phrase string) ([]string, error) {
+func (c Client) Norm(ctx context.Context, phrase string) ([]core.Token, error) {
 
 	words, err := c.client.Norm(ctx, &wordspb.WordsRequest{Phrase: phrase})
 	if err != nil {
@@ -42,8 +42,13 @@ func (c Client) Norm(ctx context.Context, phrase string) ([]string, error) {
 		return nil, err
 	}
 
+	tokens := make([]core.Token, 0, len(words.GetTokens()))
+	for _, token := range words.GetTokens() {
+		tokens = append(tokens, core.Token{Word: token.GetWord(), Position: int(token.GetPosition())})
+	}
+
 	c.log.Info("Norm successful")
-	return words.GetWords(), nil
+	return tokens, nil
 }
 
 func (c Client) Ping(ctx context.Context) error {
@@ -12,7 +12,20 @@ type Config struct {
 	Address      string        `yaml:"search_address" env:"SEARCH_ADDRESS" env-default:"localhost:80"`
 	DBAddress    string        `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
 	WordsAddress string        `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"localhost:81"`
-	IndexTTL     time.Duration `yaml:"index_ttl" env:"INDEX_TTL" env-default:"20s"`
+	IndexTTL     time.Duration `yaml:"index_ttl" env:"INDEX_TTL" env-default:"5m"`
+	SnapshotPath string        `yaml:"snapshot_path" env:"SNAPSHOT_PATH" env-default:"index.snapshot"`
+	SynonymsPath string        `yaml:"synonyms_path" env:"SYNONYMS_PATH" env-default:"synonyms.txt"`
+	// Backend is how /api/search finds comics in the database:
+	// "array" scans word arrays, "fts" uses Postgres full-text search.
+	Backend string `yaml:"search_backend" env:"SEARCH_BACKEND" env-default:"array"`
+	// CacheSize is how many search results are kept, 0 disables
+	// the cache, CacheTTL is how long they are kept.
+	CacheSize int           `yaml:"cache_size" env:"CACHE_SIZE" env-default:"1000"`
+	CacheTTL  time.Duration `yaml:"cache_ttl" env:"CACHE_TTL" env-default:"1m"`
+	// Boosts multiply relevance of words found in a comic field.
+	TitleBoost      float64 `yaml:"title_boost" env:"TITLE_BOOST" env-default:"3"`
+	AltBoost        float64 `yaml:"alt_boost" env:"ALT_BOOST" env-default:"2"`
+	TranscriptBoost float64 `yaml:"transcript_boost" env:"TRANSCRIPT_BOOST" env-default:"1"`
 }
 
 func MustLoad(configPath string) Config {
@@ -0,0 +1,113 @@
+package core
+
+// bkTree is a Burkhard-Keller tree over words with Levenshtein distance,
+// it finds all words within a given distance without scanning the whole
+// vocabulary.
+type bkTree struct {
+	root *bkNode
+	size int
+}
+
+type bkNode struct {
+	word     string
+	children map[int]*bkNode
+}
+
+func (t *bkTree) Add(word string) {
+	t.size++
+	if t.root == nil {
+		t.root = &bkNode{word: word}
+		return
+	}
+
+	node := t.root
+	for {
+		d := levenshtein(word, node.word)
+		if d == 0 {
+			t.size--
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{word: word}
+			return
+		}
+		node = child
+	}
+}
+
+// clone copies the tree, so that words added to the copy do not change
+// the original.
+func (t *bkTree) clone() bkTree {
+	return bkTree{root: t.root.clone(), size: t.size}
+}
+
+func (n *bkNode) clone() *bkNode {
+	if n == nil {
+		return nil
+	}
+	c := &bkNode{word: n.word}
+	if n.children != nil {
+		c.children = make(map[int]*bkNode, len(n.children))
+		for d, child := range n.children {
+			c.children[d] = child.clone()
+		}
+	}
+	return c
+}
+
+type bkMatch struct {
+	word     string
+	distance int
+}
+
+// Search returns every word at most maxDistance edits away from word.
+func (t *bkTree) Search(word string, maxDistance int) []bkMatch {
+	if t.root == nil {
+		return nil
+	}
+
+	var found []bkMatch
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := levenshtein(word, node.word)
+		if d <= maxDistance {
+			found = append(found, bkMatch{word: node.word, distance: d})
+		}
+		// triangle inequality: only children at distance d±max may match
+		for childDistance, child := range node.children {
+			if childDistance >= d-maxDistance && childDistance <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return found
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexSimilar(t *testing.T) {
+	index := NewIndex()
+	index.Add(1, []Token{{Word: "recurs"}, {Word: "python"}, {Word: "cat"}})
+	index.Add(2, []Token{{Word: "recur"}, {Word: "recurs"}})
+
+	tests := []struct {
+		word string
+		want []Expansion
+	}{
+		{word: "recurshun", want: []Expansion{{Word: "recurshun", Term: "recurs", Distance: 3}}},
+		{word: "recurz", want: []Expansion{
+			{Word: "recurz", Term: "recurs", Distance: 1},
+			{Word: "recurz", Term: "recur", Distance: 1},
+		}},
+		{word: "pythn", want: []Expansion{{Word: "pythn", Term: "python", Distance: 1}}},
+		{word: "dog", want: []Expansion{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			assert.Equal(t, tt.want, index.Similar(tt.word, fuzzyDistance(tt.word)))
+		})
+	}
+}
+
+func TestIndexComplete(t *testing.T) {
+	index := NewIndex()
+	index.Update([]Comics{
+		{ID: 1, Tokens: []Token{{Word: "recurs"}, {Word: "python"}, {Word: "cat"}}},
+		{ID: 2, Tokens: []Token{{Word: "recur"}, {Word: "recurs"}, {Word: "rec"}}},
+	})
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []Completion
+	}{
+		{prefix: "rec", limit: 10, want: []Completion{
+			{Word: "recurs", DocFreq: 2},
+			{Word: "rec", DocFreq: 1},
+			{Word: "recur", DocFreq: 1},
+		}},
+		{prefix: "rec", limit: 1, want: []Completion{{Word: "recurs", DocFreq: 2}}},
+		{prefix: "py", limit: 10, want: []Completion{{Word: "python", DocFreq: 1}}},
+		{prefix: "dog", limit: 10, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			assert.Equal(t, tt.want, index.Complete(tt.prefix, tt.limit))
+		})
+	}
+}
+
+func TestIndexCompleteForms(t *testing.T) {
+	run := Token{Word: "run", Field: FieldTitle}
+	late := Token{Word: "late", Position: 1, Field: FieldTitle}
+	// only titles are set, they are shorter than the starts of other fields
+	starts := []int{0, 100, 200}
+	index := NewIndex()
+	index.Update([]Comics{
+		{ID: 1, Title: "Running", FieldStarts: starts, Tokens: []Token{run}},
+		{ID: 2, Title: "Running late", FieldStarts: starts, Tokens: []Token{run, late}},
+		{ID: 3, Title: "Run", FieldStarts: starts, Tokens: []Token{run}},
+	})
+	assert.Equal(t, []Completion{{Word: "running", DocFreq: 3}}, index.Complete("ru", 10))
+
+	restored := indexFromSnapshot(index.Snapshot())
+	assert.Equal(t, []Completion{{Word: "running", DocFreq: 3}}, restored.Complete("ru", 10))
+
+	index.Update([]Comics{{ID: 2, Title: "Run late", FieldStarts: starts, Tokens: []Token{run, late}}})
+	assert.Equal(t, []Completion{{Word: "run", DocFreq: 3}}, index.Complete("ru", 10))
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// QueryCache keeps results of recent searches, the least recently used
+// one is dropped when it is full. Results older than ttl are searched
+// again, ttl 0 keeps them until the cache is cleared, size 0 disables
+// the cache.
+type QueryCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	// order has the most recently used entries in front
+	order *list.List
+	// generation changes on Clear, results of searches started before
+	// are not stored
+	generation uint64
+	hits       int
+	misses     int
+}
+
+// cacheKey is a search with the query as the service understood it,
+// so differently written queries with the same words share results.
+type cacheKey struct {
+	mode     string
+	keywords string
+	limit    int
+	offset   int
+	cursor   string
+	explain  bool
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	result  SearchResult
+	expires time.Time
+}
+
+func NewQueryCache(size int, ttl time.Duration) *QueryCache {
+	return &QueryCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[cacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func newCacheKey(mode string, query SearchQuery, root queryNode) cacheKey {
+	var keywords string
+	if root != nil {
+		keywords = root.String()
+	}
+	return cacheKey{
+		mode:     mode,
+		keywords: keywords,
+		limit:    query.Limit,
+		offset:   query.Offset,
+		cursor:   query.Cursor,
+		explain:  query.Explain,
+	}
+}
+
+// get returns the cached result and the generation to store
+// a new one with.
+func (c *QueryCache) get(key cacheKey) (SearchResult, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return SearchResult{}, c.generation, false
+	}
+
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*cacheEntry)
+		if c.ttl <= 0 || time.Now().Before(entry.expires) {
+			c.order.MoveToFront(e)
+			c.hits++
+			return entry.result, c.generation, true
+		}
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+	c.misses++
+	return SearchResult{}, c.generation, false
+}
+
+func (c *QueryCache) put(key cacheKey, result SearchResult, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 || generation != c.generation {
+		return
+	}
+
+	entry := &cacheEntry{key: key, result: result, expires: time.Now().Add(c.ttl)}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Clear drops every result, it is called when results may change.
+func (c *QueryCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	clear(c.entries)
+	c.order.Init()
+}
+
+func (c *QueryCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Size: c.order.Len()}
+}
+
+// cached returns the cached result of the parsed query or searches and
+// caches it, errors are not cached. The suggestion of a cached result is
+// written again over the query as it is written this time.
+func (s *Service) cached(mode string, query SearchQuery, parsed parsedQuery, search func() (SearchResult, error)) (SearchResult, error) {
+	key := newCacheKey(mode, query, parsed.root)
+	result, generation, ok := s.cache.get(key)
+	if ok {
+		if len(result.corrections) > 0 {
+			result.Suggestion = replaceSources(query.Keywords, parsed.sources, result.corrections)
+		}
+		return result, nil
+	}
+	result, err := search()
+	if err != nil {
+		return result, err
+	}
+	s.cache.put(key, result, generation)
+	return result, nil
+}
+
+// ResetCache drops cached results, the update service asks for it
+// after comics were added or dropped.
+func (s *Service) ResetCache() {
+	s.cache.Clear()
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache(t *testing.T) {
+	cache := NewQueryCache(2, 0)
+	key := func(keywords string) cacheKey {
+		root, err := parseQuery(context.Background(), keywords, splitNorm)
+		require.NoError(t, err)
+		return newCacheKey("index", SearchQuery{Keywords: keywords, Limit: 10}, root)
+	}
+
+	_, generation, ok := cache.get(key("cat"))
+	assert.False(t, ok)
+	cache.put(key("cat"), SearchResult{Query: "cat"}, generation)
+	cache.put(key("dog"), SearchResult{Query: "dog"}, generation)
+	result, _, ok := cache.get(key("  Cat "))
+	assert.True(t, ok)
+	assert.Equal(t, "cat", result.Query)
+
+	// dog is the least recently used
+	cache.put(key("fish"), SearchResult{Query: "fish"}, generation)
+	_, _, ok = cache.get(key("dog"))
+	assert.False(t, ok)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Size: 2}, cache.Stats())
+
+	// results of searches started before clearing are not stored
+	cache.Clear()
+	cache.put(key("cat"), SearchResult{Query: "cat"}, generation)
+	_, _, ok = cache.get(key("cat"))
+	assert.False(t, ok)
+
+	expiring := NewQueryCache(2, time.Nanosecond)
+	_, generation, _ = expiring.get(key("cat"))
+	expiring.put(key("cat"), SearchResult{Query: "cat"}, generation)
+	time.Sleep(time.Millisecond)
+	_, _, ok = expiring.get(key("cat"))
+	assert.False(t, ok)
+}
+
+func TestSearchCacheReset(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+
+	service := newTestService(t, testOptions{db: db, cacheSize: 10})
+	require.NoError(t, service.BuildIndex(context.Background()))
+
+	assert.Equal(t, []int{1}, searchIndexIDs(t, service, "cat"))
+	db.put(2, "dog chasing a cat")
+	assert.Equal(t, []int{1}, searchIndexIDs(t, service, "cat"))
+
+	require.NoError(t, service.BuildIndex(context.Background()))
+	assert.ElementsMatch(t, []int{1, 2}, searchIndexIDs(t, service, "cat"))
+
+	status, err := service.IndexStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Size: 1}, status.Cache)
+
+	service.ResetCache()
+	status, err = service.IndexStatus(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, status.Cache.Size)
+}
+
+func TestSearchCacheNormalized(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+	db.put(2, "python tables")
+
+	service := newTestService(t, testOptions{db: db, cacheSize: 10})
+	require.NoError(t, service.BuildIndex(context.Background()))
+
+	assert.Equal(t, []int{1}, searchIndexIDs(t, service, "Cat"))
+	assert.Equal(t, []int{1}, searchIndexIDs(t, service, "the  cat"))
+
+	// the cached suggestion is written over the query as it is written now
+	result, err := service.SearchIndex(context.Background(), SearchQuery{Keywords: "Cst -pythn", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "cat -pythn", result.Suggestion)
+	result, err = service.SearchIndex(context.Background(), SearchQuery{Keywords: "cst  -PYTHN", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "cat  -PYTHN", result.Suggestion)
+
+	status, err := service.IndexStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2, Size: 2}, status.Cache)
+}
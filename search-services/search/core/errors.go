@@ -4,3 +4,4 @@ import "errors"
 
 var ErrNotFound = errors.New("resource is not found")
 var ErrBadArguments = errors.New("arguments are not acceptable")
+var ErrAlreadyExists = errors.New("resource or task already exists")
@@ -0,0 +1,80 @@
+package core
+
+// explain tells how much every scored word of the query added to each
+// comic of the page.
+func explain(root queryNode, postings postingSet, page []scoredID, mode searchMode) map[int]*Explanation {
+	terms := scoredTerms(root)
+	explanations := make(map[int]*Explanation, len(page))
+	for _, r := range page {
+		explanations[r.ID] = &Explanation{Backend: mode.name, Terms: make([]TermExplanation, 0, len(terms))}
+	}
+
+	for _, term := range terms {
+		list := postings[term.word]
+		df := list.docFreq()
+		for id, e := range explanations {
+			t := TermExplanation{Term: term.word, DocFreq: df, Weight: term.weight}
+			if p, ok := list.posting(id); ok {
+				t.Matched = true
+				t.TermFreq = p.Freq
+				if mode.bm25 {
+					t.Score = bm25(p, df, mode.stats) * term.weight
+				}
+			}
+			e.Terms = append(e.Terms, t)
+		}
+	}
+	return explanations
+}
+
+// scoredTerms returns words of the query that are not excluded with
+// their weights, a word found twice keeps the larger one.
+func scoredTerms(node queryNode) []weightedTerm {
+	var terms []weightedTerm
+	add := func(word string, weight float64) {
+		for i := range terms {
+			if terms[i].word == word {
+				terms[i].weight = max(terms[i].weight, weight)
+				return
+			}
+		}
+		terms = append(terms, weightedTerm{word: word, weight: weight})
+	}
+
+	var walk func(node queryNode)
+	walk = func(node queryNode) {
+		switch n := node.(type) {
+		case termNode, phraseNode:
+			for _, word := range n.words() {
+				add(word, 1)
+			}
+		case fuzzyNode:
+			for _, e := range n.expansions {
+				add(e.Term, fuzzyWeight(e.Distance))
+			}
+		case synonymNode:
+			add(n.word, 1)
+			for _, synonym := range n.synonyms {
+				add(synonym, synonymWeight)
+			}
+		case *fieldNode:
+			walk(n.child)
+		case *nearNode:
+			walk(n.left)
+			walk(n.right)
+		case *orNode:
+			for _, child := range n.children {
+				walk(child)
+			}
+		case *boolNode:
+			for _, child := range n.must {
+				walk(child)
+			}
+			for _, child := range n.should {
+				walk(child)
+			}
+		}
+	}
+	walk(node)
+	return terms
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchExplain(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+	db.put(2, "dog chasing a cat cat")
+	db.put(3, "python")
+
+	service := newTestService(t, testOptions{db: db, cacheSize: 10})
+	require.NoError(t, service.BuildIndex(context.Background()))
+	ctx := context.Background()
+
+	result, err := service.SearchIndex(ctx, SearchQuery{Keywords: "cat dog -keyboard", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Comics, 1)
+	assert.Nil(t, result.Comics[0].Explain)
+
+	result, err = service.SearchIndex(ctx, SearchQuery{Keywords: "cat dog", Limit: 10, Explain: true})
+	require.NoError(t, err)
+	require.Len(t, result.Comics, 2)
+	for _, c := range result.Comics {
+		require.NotNil(t, c.Explain)
+		assert.Equal(t, "index", c.Explain.Backend)
+		var score float64
+		for _, term := range c.Explain.Terms {
+			score += term.Score
+		}
+		assert.InDelta(t, c.Score, score, 1e-9)
+	}
+
+	second := result.Comics[1].Explain
+	assert.Equal(t, 1, result.Comics[1].ID)
+	require.Len(t, second.Terms, 2)
+	assert.Equal(t, TermExplanation{Term: "cat", Matched: true, TermFreq: 1, DocFreq: 2, Weight: 1, Score: second.Terms[0].Score}, second.Terms[0])
+	assert.Equal(t, TermExplanation{Term: "dog", DocFreq: 1, Weight: 1}, second.Terms[1])
+	assert.Equal(t, 2, result.Comics[0].Explain.Terms[0].TermFreq)
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TextQuery is a query translated to Postgres tsquery syntax. Match selects
+// comics, Rank scores them and keeps optional parts that Match drops.
+// Weights are ts_rank_cd weights of lexeme labels D, C, B and A.
+type TextQuery struct {
+	Match   string
+	Rank    string
+	Weights []float64
+}
+
+// Words of fields are labeled in tsvector, other words have label D.
+var tsLabels = map[Field]string{
+	FieldTitle:      "A",
+	FieldAlt:        "B",
+	FieldTranscript: "D",
+}
+
+// toTextQuery translates a parsed query, comics are indexed with the
+// normalized words as lexemes so no text search configuration is applied.
+// NEAR is rejected inside NEAR: every allowed distance repeats both
+// operands, so nesting grows the tsquery about a hundred times a level.
+func toTextQuery(root queryNode, boosts FieldBoosts) (TextQuery, error) {
+	if nestedNear(root, false) {
+		return TextQuery{}, fmt.Errorf("%w: full-text search does not support NEAR inside NEAR", ErrBadArguments)
+	}
+	return TextQuery{Match: tsMatch(root, ""), Rank: tsRank(root), Weights: tsWeights(boosts)}, nil
+}
+
+// nestedNear tells whether a NEAR operand has another NEAR in it.
+func nestedNear(node queryNode, inNear bool) bool {
+	switch n := node.(type) {
+	case *fieldNode:
+		return nestedNear(n.child, inNear)
+	case *nearNode:
+		return inNear || nestedNear(n.left, true) || nestedNear(n.right, true)
+	case *orNode:
+		return slices.ContainsFunc(n.children, func(child queryNode) bool { return nestedNear(child, inNear) })
+	case *boolNode:
+		children := slices.Concat(n.must, n.should, n.mustNot)
+		return slices.ContainsFunc(children, func(child queryNode) bool { return nestedNear(child, inNear) })
+	}
+	return false
+}
+
+// tsWeights scales boosts down to at most 1 as Postgres requires.
+func tsWeights(boosts FieldBoosts) []float64 {
+	weights := []float64{boosts.weight(FieldTranscript), 1, boosts.weight(FieldAlt), boosts.weight(FieldTitle)}
+	top := slices.Max(weights)
+	for i := range weights {
+		weights[i] /= top
+	}
+	return weights
+}
+
+// tsMatch translates a node, label limits its lexemes to a field.
+func tsMatch(node queryNode, label string) string {
+	switch n := node.(type) {
+	case termNode:
+		return tsLexeme(n.word, label)
+	case phraseNode:
+		var b strings.Builder
+		b.WriteString(tsLexeme(n.tokens[0].Word, label))
+		for i := 1; i < len(n.tokens); i++ {
+			fmt.Fprintf(&b, " <%d> %s", n.tokens[i].Position-n.tokens[i-1].Position, tsLexeme(n.tokens[i].Word, label))
+		}
+		return "(" + b.String() + ")"
+	case fuzzyNode:
+		terms := make([]string, 0, len(n.expansions))
+		for _, e := range n.expansions {
+			terms = append(terms, tsLexeme(e.Term, label))
+		}
+		return "(" + strings.Join(terms, " | ") + ")"
+	case synonymNode:
+		// ts_rank_cd has no weights of lexemes, synonyms rank as the word
+		terms := make([]string, 0, len(n.synonyms)+1)
+		for _, word := range n.words() {
+			terms = append(terms, tsLexeme(word, label))
+		}
+		return "(" + strings.Join(terms, " | ") + ")"
+	case *fieldNode:
+		return tsMatch(n.child, tsLabels[n.field])
+	case *nearNode:
+		// tsquery has exact distances only, every allowed one is listed
+		left, right := tsMatch(n.left, label), tsMatch(n.right, label)
+		var parts []string
+		for d := 1; d <= n.distance; d++ {
+			parts = append(parts,
+				fmt.Sprintf("%s <%d> %s", left, d, right),
+				fmt.Sprintf("%s <%d> %s", right, d, left))
+		}
+		return "(" + strings.Join(parts, " | ") + ")"
+	case *orNode:
+		parts := make([]string, 0, len(n.children))
+		for _, child := range n.children {
+			parts = append(parts, tsMatch(child, label))
+		}
+		return "(" + strings.Join(parts, " | ") + ")"
+	case *boolNode:
+		var parts []string
+		if len(n.must) > 0 {
+			for _, child := range n.must {
+				parts = append(parts, tsMatch(child, label))
+			}
+		} else {
+			parts = append(parts, tsMatch(newOrNode(n.should), label))
+		}
+		for _, child := range n.mustNot {
+			parts = append(parts, "!"+tsMatch(child, label))
+		}
+		return "(" + strings.Join(parts, " & ") + ")"
+	}
+	return ""
+}
+
+// tsRank joins every part that is not excluded, so optional parts
+// raise the rank of comics that have them.
+func tsRank(node queryNode) string {
+	n, ok := node.(*boolNode)
+	if !ok {
+		return tsMatch(node, "")
+	}
+	parts := make([]string, 0, len(n.must)+len(n.should))
+	for _, child := range append(n.must[:len(n.must):len(n.must)], n.should...) {
+		parts = append(parts, tsMatch(child, ""))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// tsLexeme quotes a word so that tsquery operators in it are not parsed,
+// a label limits it to words of a field.
+func tsLexeme(word, label string) string {
+	word = strings.ReplaceAll(word, `\`, `\\`)
+	lexeme := "'" + strings.ReplaceAll(word, "'", "''") + "'"
+	if label != "" {
+		lexeme += ":" + label
+	}
+	return lexeme
+}
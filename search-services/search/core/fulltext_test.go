@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTextQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  TextQuery
+	}{
+		{query: `cat`, want: TextQuery{Match: `'cat'`, Rank: `'cat'`}},
+		{query: `cat dog`, want: TextQuery{Match: `('cat' | 'dog')`, Rank: `('cat' | 'dog')`}},
+		{query: `"little the bobby"`, want: TextQuery{Match: `('little' <2> 'bobby')`, Rank: `('little' <2> 'bobby')`}},
+		{query: `cat NEAR/2 dog`, want: TextQuery{
+			Match: `('cat' <1> 'dog' | 'dog' <1> 'cat' | 'cat' <2> 'dog' | 'dog' <2> 'cat')`,
+			Rank:  `('cat' <1> 'dog' | 'dog' <1> 'cat' | 'cat' <2> 'dog' | 'dog' <2> 'cat')`,
+		}},
+		{query: `+cat dog -(phone OR tablet)`, want: TextQuery{
+			Match: `('cat' & !('phone' | 'tablet'))`,
+			Rank:  `'cat' | 'dog'`,
+		}},
+		{query: `cat dog -phone`, want: TextQuery{
+			Match: `(('cat' | 'dog') & !'phone')`,
+			Rank:  `'cat' | 'dog'`,
+		}},
+		{query: `title:cat dog`, want: TextQuery{Match: `('cat':A | 'dog')`, Rank: `('cat':A | 'dog')`}},
+		{query: `alt:"little the bobby"`, want: TextQuery{Match: `('little':B <2> 'bobby':B)`, Rank: `('little':B <2> 'bobby':B)`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			root, err := parseQuery(context.Background(), tt.query, splitNorm)
+			require.NoError(t, err)
+			tt.want.Weights = []float64{1, 1, 1, 1}
+			query, err := toTextQuery(root, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, query)
+		})
+	}
+}
+
+func TestTextQueryQuotes(t *testing.T) {
+	// the words service drops quotes, but a lexeme must never break out
+	// of its quotes
+	query, err := toTextQuery(termNode{word: "it's"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `'it''s'`, query.Match)
+	assert.Equal(t, `'it''s'`, query.Rank)
+}
+
+func TestTextQueryWeights(t *testing.T) {
+	boosts := FieldBoosts{FieldTitle: 4, FieldAlt: 2}
+	query, err := toTextQuery(termNode{word: "cat"}, boosts)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.25, 0.25, 0.5, 1}, query.Weights)
+}
+
+func TestTextQueryNestedNear(t *testing.T) {
+	for _, query := range []string{
+		`a NEAR/50 b NEAR/50 c NEAR/50 d NEAR/50 e`,
+		`(a NEAR/2 b) NEAR/2 c`,
+		`a NEAR/2 (b OR (c NEAR/2 d))`,
+		`a NEAR/2 title:(b c NEAR/2 d)`,
+	} {
+		t.Run(query, func(t *testing.T) {
+			root, err := parseQuery(context.Background(), query, splitNorm)
+			require.NoError(t, err)
+			_, err = toTextQuery(root, nil)
+			assert.ErrorIs(t, err, ErrBadArguments)
+		})
+	}
+
+	// separate NEARs are fine
+	root, err := parseQuery(context.Background(), `(a NEAR/50 b) OR c NEAR/50 d`, splitNorm)
+	require.NoError(t, err)
+	_, err = toTextQuery(root, nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/RoaringBitmap/roaring/v2"
+)
+
+// maxExpansions limits how many indexed words may replace one unknown word.
+const maxExpansions = 5
+
+// fuzzyDistance is the edit distance allowed for a word of this length,
+// short words are never expanded.
+func fuzzyDistance(word string) int {
+	switch n := utf8.RuneCountInString(word); {
+	case n <= 3:
+		return 0
+	case n <= 5:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// fuzzyWeight lowers scores of expanded words so exact matches rank higher.
+func fuzzyWeight(distance int) float64 {
+	return 1 / float64(1+distance)
+}
+
+// fuzzyNode is an unknown query word replaced with similar indexed words.
+type fuzzyNode struct {
+	word       string
+	expansions []Expansion
+}
+
+func (n fuzzyNode) words() []string {
+	words := make([]string, 0, len(n.expansions))
+	for _, e := range n.expansions {
+		words = append(words, e.Term)
+	}
+	return words
+}
+
+func (n fuzzyNode) docs(postings postingSet) *roaring.Bitmap {
+	result := roaring.New()
+	for _, e := range n.expansions {
+		result.Or(postings.ids(e.Term))
+	}
+	return result
+}
+
+func (n fuzzyNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	result := make(matches)
+	for _, e := range n.expansions {
+		found := termNode{word: e.Term}.eval(postings, stats, within)
+		for _, h := range found {
+			h.score *= fuzzyWeight(e.Distance)
+		}
+		union(result, found)
+	}
+	return result
+}
+
+func (n fuzzyNode) String() string {
+	return fmt.Sprintf("%s~", n.word)
+}
+
+// expandTerms replaces unknown words of the query with their expansions.
+// Phrases and excluded parts are left exact.
+func expandTerms(node queryNode, expansions map[string][]Expansion) queryNode {
+	switch n := node.(type) {
+	case termNode:
+		if e, ok := expansions[n.word]; ok {
+			return fuzzyNode{word: n.word, expansions: e}
+		}
+	case *fieldNode:
+		return &fieldNode{field: n.field, child: expandTerms(n.child, expansions)}
+	case *nearNode:
+		return &nearNode{
+			left:     expandTerms(n.left, expansions),
+			right:    expandTerms(n.right, expansions),
+			distance: n.distance,
+		}
+	case *orNode:
+		children := make([]queryNode, 0, len(n.children))
+		for _, child := range n.children {
+			children = append(children, expandTerms(child, expansions))
+		}
+		return &orNode{children: children}
+	case *boolNode:
+		expanded := &boolNode{mustNot: n.mustNot}
+		for _, child := range n.must {
+			expanded.must = append(expanded.must, expandTerms(child, expansions))
+		}
+		for _, child := range n.should {
+			expanded.should = append(expanded.should, expandTerms(child, expansions))
+		}
+		return expanded
+	}
+	return node
+}
+
+// queryWords returns words of the query that are not excluded,
+// words of phrases are included if phrases is set.
+func queryWords(node queryNode, phrases bool) []string {
+	switch n := node.(type) {
+	case termNode:
+		return []string{n.word}
+	case phraseNode:
+		if phrases {
+			return n.words()
+		}
+	case fuzzyNode, synonymNode:
+		return n.words()
+	case *fieldNode:
+		return queryWords(n.child, phrases)
+	case *nearNode:
+		return append(queryWords(n.left, phrases), queryWords(n.right, phrases)...)
+	case *orNode:
+		var words []string
+		for _, child := range n.children {
+			words = append(words, queryWords(child, phrases)...)
+		}
+		return words
+	case *boolNode:
+		var words []string
+		for _, child := range append(slices.Clone(n.must), n.should...) {
+			words = append(words, queryWords(child, phrases)...)
+		}
+		return words
+	}
+	return nil
+}
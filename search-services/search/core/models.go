@@ -1,6 +1,15 @@
 package core
 
-import "sync"
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RoaringBitmap/roaring/v2"
+)
 
 type ServiceStatus string
 
@@ -9,14 +18,146 @@ const (
 	StatusIdle    ServiceStatus = "idle"
 )
 
+// IndexStatus describes the in-memory index and its last build.
+type IndexStatus struct {
+	Status     ServiceStatus
+	LastBuild  time.Time
+	Duration   time.Duration
+	Comics     int
+	Vocabulary int
+	LastError  string
+	Cache      CacheStats
+}
+
+// CacheStats counts searches answered from the query cache.
+type CacheStats struct {
+	Hits   int
+	Misses int
+	Size   int
+}
+
 type SearchQuery struct {
 	Keywords string
 	Limit    int
+	// Offset skips the best comics, Cursor continues after the page
+	// it was returned with, only one of them may be set.
+	Offset int
+	Cursor string
+	// Explain asks to explain the score of every found comic.
+	Explain bool
+}
+
+// Field is the part of a comic a word comes from, words of comics
+// fetched before fields were stored have none.
+type Field string
+
+const (
+	FieldTitle      Field = "title"
+	FieldAlt        Field = "alt"
+	FieldTranscript Field = "transcript"
+)
+
+// Fields lists every field a query can be limited to.
+var Fields = []Field{FieldTitle, FieldAlt, FieldTranscript}
+
+// FieldBoosts multiply scores of words found in a field,
+// words of other fields weigh 1.
+type FieldBoosts map[Field]float64
+
+func (b FieldBoosts) weight(field Field) float64 {
+	if boost, ok := b[field]; ok {
+		return boost
+	}
+	return 1
+}
+
+type Token struct {
+	Word     string
+	Position int
+	Field    Field
 }
 
 type Comics struct {
-	ID    int
-	URL   string
+	ID  int
+	URL string
+	// Title, Alt and Transcript are original texts, comics fetched before
+	// they were stored have none.
+	Title      string
+	Alt        string
+	Transcript string
+	// FieldStarts are positions of the first words of the title, the alt
+	// text and the transcript, comics without texts have none.
+	FieldStarts []int
+	// Published is zero for comics fetched without the date.
+	Published time.Time
+	Tokens    []Token
+	Score     float64
+	// Snippet is a piece of the comic text with found words in <b>.
+	Snippet string
+	// Version grows every time the comic is added or changed.
+	Version int64
+	// Forms are original words of the comic written differently than
+	// their stems, snapshots carry them instead of texts.
+	Forms map[string]string
+	// Explain is set for found comics when it was asked for.
+	Explain *Explanation
+}
+
+// Explanation shows how the score of a found comic was made up:
+// which backend found it and what every query word added.
+type Explanation struct {
+	Backend string
+	Terms   []TermExplanation
+}
+
+// TermExplanation is a normalized query word in a found comic. Score is
+// the BM25 score of the word on its own times its Weight in the query,
+// lower for fuzzy expansions and synonyms. Phrases, NEAR and fields
+// restrict matches but are not part of the word score. Full-text search
+// scores are not split by words, so Score is 0 with that backend.
+type TermExplanation struct {
+	Term     string
+	Matched  bool
+	TermFreq int
+	DocFreq  int
+	Weight   float64
+	Score    float64
+}
+
+type SearchResult struct {
+	Comics []Comics
+	// Query is the normalized query as it was understood by the service.
+	Query string
+	// Expansions lists indexed words used instead of unknown query words.
+	Expansions []Expansion
+	// Suggestion is a corrected query offered when nothing was found.
+	Suggestion string
+	// Total is the number of all found comics, not only of this page.
+	Total int
+	// NextCursor requests the next page, it is empty on the last one.
+	NextCursor string
+
+	// corrections are the written forms of corrected query words,
+	// Suggestion is built from them.
+	corrections map[string]string
+}
+
+// Completion is an indexed word starting with a requested prefix.
+type Completion struct {
+	Word    string
+	DocFreq int
+}
+
+// Expansion is a replacement of a misspelled query word with a similar
+// indexed one.
+type Expansion struct {
+	Word     string
+	Term     string
+	Distance int
+}
+
+// SynonymGroup is a set of words searched in place of each other.
+type SynonymGroup struct {
 	Words []string
 }
 
@@ -25,35 +166,474 @@ type NormQuery struct {
 	Limit int
 }
 
+// Posting is a single occurrence record of a word: the comic it was found in,
+// how many times it occurs there, at which positions and in which fields,
+// and the total number of words in that comic.
+type Posting struct {
+	ID        int
+	Freq      int
+	Len       int
+	Positions []int
+	// Fields go along with Positions, they are empty for comics
+	// stored without fields.
+	Fields []Field
+}
+
+// CorpusStats describes the whole collection for relevance scoring.
+type CorpusStats struct {
+	Docs   int
+	AvgLen float64
+	// Boosts weigh word occurrences by their field.
+	Boosts FieldBoosts
+}
+
+// Snapshot is the index content saved between restarts, comics carry
+// only ID, Tokens and Forms.
+type Snapshot struct {
+	Version int64
+	Comics  []Comics
+}
+
+// docTerm is what a posting list keeps about a word in one comic,
+// fields go along with positions and are empty for comics stored without
+// fields.
+type docTerm struct {
+	freq      int32
+	length    int32
+	positions []int
+	fields    []Field
+}
+
+// indexedDoc is what the index keeps about a comic to replace it later,
+// forms go along with words and are empty for words written as stemmed.
+type indexedDoc struct {
+	words  []string
+	forms  []string
+	length int
+}
+
+// Index is the in-memory inverted index: comics of every word as a bitmap
+// and occurrences of the word in them in the order of the bitmap. Version
+// is the highest comics version added to it, comics changed after that
+// are not indexed yet.
 type Index struct {
-	index map[string][]int
-	lock  sync.RWMutex
+	ids   map[string]*roaring.Bitmap
+	terms map[string][]docTerm
+	// changes are added and removed (nil) occurrences of words by comic
+	// until refresh
+	changes    map[string]map[int]*docTerm
+	vocabulary bkTree
+	sorted     []string
+	// forms counts comics by the way they write a stem, only for forms
+	// that differ from the stem
+	forms    map[string]map[string]int
+	docs     map[int]indexedDoc
+	totalLen int
+	version  int64
+	lock     sync.RWMutex
 }
 
 func NewIndex() *Index {
 	return &Index{
-		index: make(map[string][]int),
+		ids:     make(map[string]*roaring.Bitmap),
+		terms:   make(map[string][]docTerm),
+		changes: make(map[string]map[int]*docTerm),
+		forms:   make(map[string]map[string]int),
+		docs:    make(map[int]indexedDoc),
+	}
+}
+
+// clone copies the index to update it while searches keep using the
+// original. Posting lists are replaced rather than changed, so they are
+// shared.
+func (i *Index) clone() *Index {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	c := &Index{
+		ids:        maps.Clone(i.ids),
+		terms:      maps.Clone(i.terms),
+		changes:    make(map[string]map[int]*docTerm),
+		vocabulary: i.vocabulary.clone(),
+		sorted:     i.sorted,
+		forms:      make(map[string]map[string]int, len(i.forms)),
+		docs:       maps.Clone(i.docs),
+		totalLen:   i.totalLen,
+		version:    i.version,
+	}
+	for word, counts := range i.forms {
+		c.forms[word] = maps.Clone(counts)
 	}
+	return c
 }
 
-func (i *Index) Drop() {
+func (i *Index) Add(id int, tokens []Token) {
 	i.lock.Lock()
-	i.index = make(map[string][]int)
+	i.add(id, tokens, nil)
+	i.refresh()
 	i.lock.Unlock()
 }
 
-func (i *Index) Add(id int, words []string) {
+// Update adds new and replaces changed comics at once, so readers see
+// either none or all of them.
+func (i *Index) Update(comics []Comics) {
 	i.lock.Lock()
-	for _, word := range words {
-		i.index[word] = append(i.index[word], id)
+	defer i.lock.Unlock()
+
+	for _, c := range comics {
+		i.add(c.ID, c.Tokens, c.surfaceForms())
+		i.version = max(i.version, c.Version)
 	}
-	i.lock.Unlock()
+	i.commit()
 }
 
-func (i *Index) Get(word string) []int {
+// Put adds a comic to an index nobody searches yet, Commit has to be
+// called once all comics are put.
+func (i *Index) Put(c Comics) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.add(c.ID, c.Tokens, c.surfaceForms())
+	i.version = max(i.version, c.Version)
+}
+
+// Commit prepares put comics for searching.
+func (i *Index) Commit() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.commit()
+}
+
+func (i *Index) commit() {
+	i.refresh()
+	i.sorted = slices.Sorted(maps.Keys(i.ids))
+}
+
+// Snapshot restores comic tokens from the posting lists.
+func (i *Index) Snapshot() Snapshot {
 	i.lock.RLock()
 	defer i.lock.RUnlock()
-	ids := make([]int, 0, 1000)
-	ids = append(ids, i.index[word]...)
-	return ids
+
+	tokens := make(map[int][]Token, len(i.docs))
+	for word := range i.ids {
+		i.list(word).each(nil, func(p Posting) {
+			for j, pos := range p.Positions {
+				token := Token{Word: word, Position: pos}
+				if j < len(p.Fields) {
+					token.Field = p.Fields[j]
+				}
+				tokens[p.ID] = append(tokens[p.ID], token)
+			}
+		})
+	}
+
+	snapshot := Snapshot{Version: i.version, Comics: make([]Comics, 0, len(i.docs))}
+	for _, id := range slices.Sorted(maps.Keys(i.docs)) {
+		var forms map[string]string
+		doc := i.docs[id]
+		for j, form := range doc.forms {
+			if form == "" {
+				continue
+			}
+			if forms == nil {
+				forms = make(map[string]string)
+			}
+			forms[doc.words[j]] = form
+		}
+		slices.SortFunc(tokens[id], func(a, b Token) int {
+			if c := cmp.Compare(a.Position, b.Position); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.Word, b.Word)
+		})
+		snapshot.Comics = append(snapshot.Comics, Comics{ID: id, Tokens: tokens[id], Forms: forms})
+	}
+	return snapshot
+}
+
+func indexFromSnapshot(snapshot Snapshot) *Index {
+	index := NewIndex()
+	index.Update(snapshot.Comics)
+	index.version = snapshot.Version
+	return index
+}
+
+func (i *Index) Version() int64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.version
+}
+
+func (i *Index) add(id int, tokens []Token, forms map[string]string) {
+	i.remove(id)
+
+	terms := make(map[string]*docTerm, len(tokens))
+	withFields := false
+	for _, token := range tokens {
+		term, ok := terms[token.Word]
+		if !ok {
+			term = &docTerm{length: int32(len(tokens))}
+			terms[token.Word] = term
+		}
+		term.freq++
+		term.positions = append(term.positions, token.Position)
+		term.fields = append(term.fields, token.Field)
+		withFields = withFields || token.Field != ""
+	}
+
+	doc := indexedDoc{words: make([]string, 0, len(terms)), length: len(tokens)}
+	for word, term := range terms {
+		if !withFields {
+			term.fields = nil
+		}
+		if _, ok := i.ids[word]; !ok {
+			i.vocabulary.Add(word)
+		}
+		i.change(word)[id] = term
+		doc.words = append(doc.words, word)
+
+		form, ok := forms[word]
+		if !ok || form == word {
+			continue
+		}
+		if doc.forms == nil {
+			doc.forms = make([]string, len(terms))
+		}
+		doc.forms[len(doc.words)-1] = form
+		if i.forms[word] == nil {
+			i.forms[word] = make(map[string]int)
+		}
+		i.forms[word][form]++
+	}
+	i.docs[id] = doc
+	i.totalLen += len(tokens)
+}
+
+// remove drops a comic indexed before. Words left without comics stay
+// in the vocabulary tree but are never suggested.
+func (i *Index) remove(id int) {
+	doc, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for _, word := range doc.words {
+		i.change(word)[id] = nil
+	}
+	for j, form := range doc.forms {
+		if form == "" {
+			continue
+		}
+		word := doc.words[j]
+		if i.forms[word][form]--; i.forms[word][form] == 0 {
+			delete(i.forms[word], form)
+		}
+		if len(i.forms[word]) == 0 {
+			delete(i.forms, word)
+		}
+	}
+	i.totalLen -= doc.length
+	delete(i.docs, id)
+}
+
+func (i *Index) change(word string) map[int]*docTerm {
+	changed, ok := i.changes[word]
+	if !ok {
+		changed = make(map[int]*docTerm)
+		i.changes[word] = changed
+	}
+	return changed
+}
+
+// refresh builds new posting lists of changed words instead of modifying
+// the old ones, so lists handed out by lookup stay the same.
+func (i *Index) refresh() {
+	for word, changed := range i.changes {
+		old := i.list(word)
+		all := roaring.New()
+		if old.ids != nil {
+			all.Or(old.ids)
+		}
+		for id := range changed {
+			all.Add(uint32(id))
+		}
+
+		ids := roaring.New()
+		terms := make([]docTerm, 0, all.GetCardinality())
+		k := 0
+		for it := all.Iterator(); it.HasNext(); {
+			id := it.Next()
+			var term *docTerm
+			if old.ids != nil && old.ids.Contains(id) {
+				term = &old.terms[k]
+				k++
+			}
+			if t, ok := changed[int(id)]; ok {
+				term = t
+			}
+			if term == nil {
+				continue
+			}
+			ids.Add(id)
+			terms = append(terms, *term)
+		}
+
+		if ids.IsEmpty() {
+			delete(i.ids, word)
+			delete(i.terms, word)
+			continue
+		}
+		ids.RunOptimize()
+		i.ids[word] = ids
+		i.terms[word] = slices.Clip(terms)
+	}
+	clear(i.changes)
+}
+
+// list returns the posting list of a word, the caller holds the lock.
+func (i *Index) list(word string) postingList {
+	return postingList{ids: i.ids[word], terms: i.terms[word]}
+}
+
+// lookup returns the posting list of a word. Lists are replaced rather
+// than changed, so it is shared with the index without copying.
+func (i *Index) lookup(word string) postingList {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	list := i.list(word)
+	if list.ids == nil {
+		list.ids = roaring.New()
+	}
+	return list
+}
+
+// comicPostings returns postings of every word of a comic by the word.
+func (i *Index) comicPostings(id int) map[string]Posting {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	doc, ok := i.docs[id]
+	if !ok {
+		return nil
+	}
+	postings := make(map[string]Posting, len(doc.words))
+	for _, word := range doc.words {
+		if p, ok := i.list(word).posting(id); ok {
+			postings[word] = p
+		}
+	}
+	return postings
+}
+
+// docFreq is the number of comics with the word, the caller holds the lock.
+func (i *Index) docFreq(word string) int {
+	return i.list(word).docFreq()
+}
+
+// Form returns the way most comics write an indexed word.
+func (i *Index) Form(word string) string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.form(word)
+}
+
+// form returns the way most comics write a stem, the caller holds
+// the lock.
+func (i *Index) form(word string) string {
+	best := word
+	// comics not counted in forms write it as the stem
+	bestCount := i.docFreq(word)
+	for _, count := range i.forms[word] {
+		bestCount -= count
+	}
+	for form, count := range i.forms[word] {
+		if count > bestCount || count == bestCount && form < best {
+			best, bestCount = form, count
+		}
+	}
+	return best
+}
+
+func (i *Index) Stats() CorpusStats {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	docs := len(i.docs)
+	if docs == 0 {
+		return CorpusStats{}
+	}
+	return CorpusStats{Docs: docs, AvgLen: float64(i.totalLen) / float64(docs)}
+}
+
+// VocabularySize returns the number of distinct indexed words.
+func (i *Index) VocabularySize() int {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return len(i.ids)
+}
+
+// Similar returns indexed words at most maxDistance edits away from word,
+// closest and most frequent first.
+func (i *Index) Similar(word string, maxDistance int) []Expansion {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	found := slices.DeleteFunc(i.vocabulary.Search(word, maxDistance), func(m bkMatch) bool {
+		return i.docFreq(m.word) == 0
+	})
+	slices.SortFunc(found, func(a, b bkMatch) int {
+		if c := cmp.Compare(a.distance, b.distance); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(i.docFreq(b.word), i.docFreq(a.word)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.word, b.word)
+	})
+
+	expansions := make([]Expansion, 0, len(found))
+	for _, m := range found {
+		expansions = append(expansions, Expansion{Word: word, Term: m.word, Distance: m.distance})
+	}
+	return expansions
+}
+
+// Suggest picks the replacement for an unknown word that is both close
+// to it and frequent in the comics.
+func (i *Index) Suggest(word string) (string, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	var (
+		best      string
+		bestScore float64
+	)
+	for _, m := range i.vocabulary.Search(word, max(1, fuzzyDistance(word))) {
+		if m.distance == 0 {
+			continue
+		}
+		score := float64(i.docFreq(m.word)) / float64((1+m.distance)*(1+m.distance))
+		if score > bestScore || score == bestScore && m.word < best {
+			best, bestScore = m.word, score
+		}
+	}
+	return best, best != ""
+}
+
+// Complete returns up to limit indexed words starting with prefix,
+// the ones found in most comics first. Words are stems, completions are
+// written the way most comics write them.
+func (i *Index) Complete(prefix string, limit int) []Completion {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	start, _ := slices.BinarySearch(i.sorted, prefix)
+	var completions []Completion
+	for _, word := range i.sorted[start:] {
+		if !strings.HasPrefix(word, prefix) {
+			break
+		}
+		completions = append(completions, Completion{Word: i.form(word), DocFreq: i.docFreq(word)})
+	}
+
+	slices.SortStableFunc(completions, func(a, b Completion) int {
+		return cmp.Compare(b.DocFreq, a.DocFreq)
+	})
+	return completions[:min(len(completions), limit)]
 }
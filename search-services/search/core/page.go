@@ -0,0 +1,76 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+)
+
+// pageCursor points past the last comic of a page. The place is kept by
+// score and ID rather than by number, so pages do not shift when comics
+// are added between requests.
+type pageCursor struct {
+	// Query is a checksum of the normalized query the cursor was made for.
+	Query uint32  `json:"q"`
+	Score float64 `json:"s"`
+	ID    int     `json:"i"`
+}
+
+func (c pageCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func parseCursor(token string) (pageCursor, error) {
+	var c pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("%w: bad cursor", ErrBadArguments)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: bad cursor", ErrBadArguments)
+	}
+	return c, nil
+}
+
+// paginate returns the ranked comics of the requested page and the cursor
+// of the next one, empty on the last page.
+func paginate(ranked []scoredID, query SearchQuery, normalized string) ([]scoredID, string, error) {
+	if query.Offset < 0 {
+		return nil, "", fmt.Errorf("%w: negative offset", ErrBadArguments)
+	}
+	if query.Offset > 0 && query.Cursor != "" {
+		return nil, "", fmt.Errorf("%w: offset and cursor can not be used together", ErrBadArguments)
+	}
+	checksum := crc32.ChecksumIEEE([]byte(normalized))
+
+	start := min(query.Offset, len(ranked))
+	if query.Cursor != "" {
+		cursor, err := parseCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if cursor.Query != checksum {
+			return nil, "", fmt.Errorf("%w: cursor belongs to another query", ErrBadArguments)
+		}
+		start = len(ranked)
+		for i, r := range ranked {
+			if r.Score < cursor.Score || r.Score == cursor.Score && r.ID > cursor.ID {
+				start = i
+				break
+			}
+		}
+	}
+
+	end := len(ranked)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, len(ranked))
+	}
+	page := ranked[start:end]
+	if end == len(ranked) || len(page) == 0 {
+		return page, "", nil
+	}
+	last := page[len(page)-1]
+	return page, pageCursor{Query: checksum, Score: last.Score, ID: last.ID}.String(), nil
+}
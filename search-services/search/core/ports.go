@@ -3,18 +3,60 @@ package core
 import "context"
 
 type Searcher interface {
-	Search(ctx context.Context, query SearchQuery) ([]Comics, error)
-	SearchIndex(ctx context.Context, query SearchQuery) ([]Comics, error)
+	Search(ctx context.Context, query SearchQuery) (SearchResult, error)
+	SearchIndex(ctx context.Context, query SearchQuery) (SearchResult, error)
+	SearchStream(ctx context.Context, query SearchQuery, send func(SearchResult) error) error
+	Suggest(ctx context.Context, prefix string, limit int) ([]Completion, error)
+	Similar(ctx context.Context, id, limit int) ([]Comics, error)
+	GetComic(ctx context.Context, id int) (Comics, error)
+	Random(ctx context.Context, keywords string) (Comics, error)
+	Latest(ctx context.Context) (Comics, error)
 	BuildIndex(ctx context.Context) error
+	IndexStatus(ctx context.Context) (IndexStatus, error)
+	ResetCache()
+	LoadSynonyms(ctx context.Context) error
+	Synonyms(ctx context.Context) ([]SynonymGroup, error)
+	AddSynonyms(ctx context.Context, group SynonymGroup) error
+	RemoveSynonyms(ctx context.Context, word string) error
+}
+
+// Snapshots keeps the index between restarts, Load returns ErrNotFound
+// when nothing was saved yet.
+type Snapshots interface {
+	Save(snapshot Snapshot) error
+	Load() (Snapshot, error)
+}
+
+// SynonymStore keeps synonym groups, Load returns ErrNotFound when
+// there are none.
+type SynonymStore interface {
+	Save(groups []SynonymGroup) error
+	Load() ([]SynonymGroup, error)
 }
 
 type DB interface {
 	CheckDB() error
-	Search(ctx context.Context, keyword string) ([]int, error)
+	Search(ctx context.Context, keyword string) ([]Posting, error)
+	// FullText returns IDs of all comics matching the query with their
+	// full-text rank, the best first.
+	FullText(ctx context.Context, query TextQuery) ([]Comics, error)
+	Stats(ctx context.Context) (CorpusStats, error)
+	// Get returns ErrNotFound if there is no such comic.
 	Get(ctx context.Context, id int) (Comics, error)
+	// GetMany returns the comics found by IDs in no particular order.
+	GetMany(ctx context.Context, ids []int) ([]Comics, error)
+	// Updated streams comics added or changed after the given version
+	// with their texts to fn in version order, it stops on the first
+	// error of fn.
+	Updated(ctx context.Context, version int64, fn func(Comics) error) error
+	// RandomID returns the ID of a comic picked uniformly at random,
+	// ErrNotFound if there are no comics.
+	RandomID(ctx context.Context) (int, error)
 	MaxId(ctx context.Context) (int, error)
 }
 
 type Words interface {
-	Norm(ctx context.Context, phrase string) ([]string, error)
+	// Norm returns tokens of the phrase, positions count runs of letters
+	// and digits, stop words included.
+	Norm(ctx context.Context, phrase string) ([]Token, error)
 }
@@ -0,0 +1,937 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/RoaringBitmap/roaring/v2"
+)
+
+// Query language:
+//
+//	word               comics containing the word
+//	"some phrase"      comics containing the words in this exact order,
+//	                   stop words keep their places
+//	a NEAR/n b         comics where a and b are at most n words apart,
+//	                   in any order, n is from 1 to maxNearDistance
+//	+a, a AND b        a must be present
+//	-a, NOT a          a must not be present
+//	a OR b             either a or b
+//	( ... )            grouping
+//	title:a            a only in the title, also alt: and transcript:,
+//	                   a may be a word, a "phrase" or a (group)
+//
+// Parts without an operator are optional: at least one of them has to match
+// unless something is required, matches of every part add to the BM25 score.
+// OR binds weaker than a sequence of parts, NEAR binds stronger.
+
+type lexKind int
+
+const (
+	lexWord lexKind = iota
+	lexPhrase
+	lexNear
+	lexOpen
+	lexClose
+	lexOr
+	lexAnd
+	lexNot
+	lexRequired
+	lexField
+)
+
+type lexeme struct {
+	kind     lexKind
+	text     string
+	distance int
+	// start is the byte offset of text in the query
+	start int
+}
+
+const (
+	nearPrefix = "NEAR/"
+	// maxNearDistance keeps NEAR within a field, the full-text backend
+	// also lists every allowed distance in the tsquery.
+	maxNearDistance = 50
+)
+
+var keywords = map[string]lexKind{
+	"OR":  lexOr,
+	"AND": lexAnd,
+	"NOT": lexNot,
+}
+
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || r == '"' || r == '(' || r == ')'
+}
+
+func lex(query string) ([]lexeme, error) {
+	var lexemes []lexeme
+	runes := []rune(query)
+	offsets := make([]int, 0, len(runes)+1)
+	for i := range query {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(query))
+	for i := 0; i < len(runes); {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+		case runes[i] == '(':
+			lexemes = append(lexemes, lexeme{kind: lexOpen, text: "("})
+			i++
+		case runes[i] == ')':
+			lexemes = append(lexemes, lexeme{kind: lexClose, text: ")"})
+			i++
+		case runes[i] == '"':
+			end := slices.Index(runes[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated quote", ErrBadArguments)
+			}
+			lexemes = append(lexemes, lexeme{kind: lexPhrase, text: string(runes[i+1 : i+1+end]), start: offsets[i+1]})
+			i += end + 2
+		case (runes[i] == '+' || runes[i] == '-') && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			kind := lexRequired
+			if runes[i] == '-' {
+				kind = lexNot
+			}
+			lexemes = append(lexemes, lexeme{kind: kind, text: string(runes[i])})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !isDelimiter(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			if field, rest, ok := cutField(word); ok {
+				lexemes = append(lexemes, lexeme{kind: lexField, text: string(field)})
+				if rest == "" && (i == len(runes) || unicode.IsSpace(runes[i]) || runes[i] == ')') {
+					return nil, fmt.Errorf("%w: nothing after %s:", ErrBadArguments, field)
+				}
+				if rest != "" {
+					lexemes = append(lexemes, lexeme{kind: lexWord, text: rest, start: offsets[i] - len(rest)})
+				}
+				continue
+			}
+			if kind, ok := keywords[word]; ok {
+				lexemes = append(lexemes, lexeme{kind: kind, text: word})
+				continue
+			}
+			if !strings.HasPrefix(word, nearPrefix) {
+				lexemes = append(lexemes, lexeme{kind: lexWord, text: word, start: offsets[start]})
+				continue
+			}
+			distance, err := strconv.Atoi(strings.TrimPrefix(word, nearPrefix))
+			if err != nil || distance < 1 || distance > maxNearDistance {
+				return nil, fmt.Errorf("%w: bad proximity operator %q, the distance is from 1 to %d",
+					ErrBadArguments, word, maxNearDistance)
+			}
+			lexemes = append(lexemes, lexeme{kind: lexNear, text: word, distance: distance})
+		}
+	}
+	return lexemes, nil
+}
+
+// cutField splits a word like title:cat into the field and the rest.
+func cutField(word string) (Field, string, bool) {
+	name, rest, ok := strings.Cut(word, ":")
+	if !ok || !slices.Contains(Fields, Field(name)) {
+		return "", "", false
+	}
+	return Field(name), rest, true
+}
+
+type normalizer func(ctx context.Context, phrase string) ([]Token, error)
+
+type parser struct {
+	lexemes []lexeme
+	// tokens are normalized words of every lexeme, by lexeme index
+	tokens [][]Token
+	pos    int
+	// excluded is how many excluded parts the parser is in
+	excluded int
+	sources  []sourceWord
+}
+
+// sourceWord is where a normalized query word was written in the query,
+// as byte offsets with the end excluded.
+type sourceWord struct {
+	word       string
+	start, end int
+}
+
+// parseQuery turns a raw user query into a tree of normalized query nodes.
+// It returns nil if nothing searchable is left after normalization.
+func parseQuery(ctx context.Context, query string, norm normalizer) (queryNode, error) {
+	root, _, err := parseQuerySources(ctx, query, norm)
+	return root, err
+}
+
+// parseQuerySources parses a query like parseQuery and also tells where
+// words of the tree that are not excluded came from.
+func parseQuerySources(ctx context.Context, query string, norm normalizer) (queryNode, []sourceWord, error) {
+	lexemes, err := lex(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens, err := normalizeLexemes(ctx, lexemes, norm)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &parser{lexemes: lexemes, tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !p.done() {
+		return nil, nil, fmt.Errorf("%w: unexpected %q", ErrBadArguments, p.peek().text)
+	}
+	return root, p.sources, nil
+}
+
+// normalizeLexemes normalizes words and phrases of a query with a single
+// call: their texts are joined and the tokens are split back by positions,
+// counting words the way the words service does. Positions of tokens are
+// relative to the start of their lexeme.
+func normalizeLexemes(ctx context.Context, lexemes []lexeme, norm normalizer) ([][]Token, error) {
+	type bounds struct {
+		lexeme     int
+		start, end int
+	}
+	var (
+		texts    []string
+		parts    []bounds
+		position int
+	)
+	for i, lx := range lexemes {
+		if lx.kind != lexWord && lx.kind != lexPhrase {
+			continue
+		}
+		n := len(textWords(lx.text))
+		if n == 0 {
+			continue
+		}
+		texts = append(texts, lx.text)
+		parts = append(parts, bounds{lexeme: i, start: position, end: position + n})
+		position += n
+	}
+
+	tokens := make([][]Token, len(lexemes))
+	if len(texts) == 0 {
+		return tokens, nil
+	}
+	all, err := norm(ctx, strings.Join(texts, " "))
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range all {
+		i, ok := slices.BinarySearchFunc(parts, token.Position, func(b bounds, position int) int {
+			switch {
+			case b.end <= position:
+				return -1
+			case b.start > position:
+				return 1
+			}
+			return 0
+		})
+		if !ok {
+			continue
+		}
+		token.Position -= parts[i].start
+		tokens[parts[i].lexeme] = append(tokens[parts[i].lexeme], token)
+	}
+	return tokens, nil
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.lexemes)
+}
+
+func (p *parser) peek() lexeme {
+	return p.lexemes[p.pos]
+}
+
+// parseOr parses parts separated by OR up to the end of the query
+// or a closing parenthesis.
+func (p *parser) parseOr() (queryNode, error) {
+	var children []queryNode
+	for {
+		node, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			children = append(children, node)
+		}
+		if p.done() || p.peek().kind != lexOr {
+			return newOrNode(children), nil
+		}
+		p.pos++
+	}
+}
+
+// parseSequence parses parts of the query placed one after another,
+// each of them optional, required or excluded.
+func (p *parser) parseSequence() (queryNode, error) {
+	node := &boolNode{}
+	parts := 0
+	requireNext := false
+
+	for !p.done() {
+		lx := p.peek()
+		switch lx.kind {
+		case lexOr, lexClose:
+			if parts == 0 {
+				return nil, fmt.Errorf("%w: nothing before %q", ErrBadArguments, lx.text)
+			}
+			if requireNext {
+				return nil, fmt.Errorf("%w: nothing after AND", ErrBadArguments)
+			}
+			return node.simplify()
+		case lexAnd:
+			if parts == 0 {
+				return nil, fmt.Errorf("%w: nothing before AND", ErrBadArguments)
+			}
+			p.pos++
+			// the part before AND becomes required as well
+			if last := len(node.should) - 1; node.lastShould && last >= 0 {
+				node.must = append(node.must, node.should[last])
+				node.should = node.should[:last]
+			}
+			requireNext = true
+			continue
+		}
+
+		modifier := lexWord
+		if lx.kind == lexRequired || lx.kind == lexNot {
+			modifier = lx.kind
+			p.pos++
+			if p.done() {
+				return nil, fmt.Errorf("%w: nothing after %q", ErrBadArguments, lx.text)
+			}
+		}
+		if requireNext && modifier == lexWord {
+			modifier = lexRequired
+		}
+		requireNext = false
+
+		if modifier == lexNot {
+			p.excluded++
+		}
+		operand, err := p.parseNear()
+		if modifier == lexNot {
+			p.excluded--
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts++
+		node.lastShould = false
+		if operand == nil {
+			continue
+		}
+		switch modifier {
+		case lexRequired:
+			node.must = append(node.must, operand)
+		case lexNot:
+			node.mustNot = append(node.mustNot, operand)
+		default:
+			node.should = append(node.should, operand)
+			node.lastShould = true
+		}
+	}
+
+	if requireNext {
+		return nil, fmt.Errorf("%w: nothing after AND", ErrBadArguments)
+	}
+	return node.simplify()
+}
+
+func (p *parser) parseNear() (queryNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.peek().kind == lexNear {
+		distance := p.peek().distance
+		p.pos++
+		if p.done() {
+			return nil, fmt.Errorf("%w: %s%d needs a right operand", ErrBadArguments, nearPrefix, distance)
+		}
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		// operands made of stop words only do not constrain anything
+		switch {
+		case left == nil:
+			left = right
+		case right != nil:
+			left = &nearNode{left: left, right: right, distance: distance}
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseOperand() (queryNode, error) {
+	lx := p.peek()
+	p.pos++
+
+	switch lx.kind {
+	case lexOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != lexClose {
+			return nil, fmt.Errorf("%w: missing closing parenthesis", ErrBadArguments)
+		}
+		p.pos++
+		return node, nil
+	case lexPhrase:
+		tokens := p.tokens[p.pos-1]
+		p.addSources(lx, tokens)
+		if len(tokens) == 0 {
+			return nil, nil
+		}
+		if len(tokens) == 1 {
+			return termNode{word: tokens[0].Word}, nil
+		}
+		return phraseNode{tokens: tokens}, nil
+	case lexWord:
+		tokens := p.tokens[p.pos-1]
+		p.addSources(lx, tokens)
+		terms := make([]queryNode, 0, len(tokens))
+		for _, token := range tokens {
+			terms = append(terms, termNode{word: token.Word})
+		}
+		return newOrNode(terms), nil
+	case lexField:
+		node, err := p.parseOperand()
+		if err != nil || node == nil {
+			return nil, err
+		}
+		return &fieldNode{field: Field(lx.text), child: node}, nil
+	case lexNear:
+		return nil, fmt.Errorf("%w: %s%d needs a left operand", ErrBadArguments, nearPrefix, lx.distance)
+	}
+	return nil, fmt.Errorf("%w: unexpected %q", ErrBadArguments, lx.text)
+}
+
+// addSources remembers where tokens of a lexeme were written unless
+// they are excluded.
+func (p *parser) addSources(lx lexeme, tokens []Token) {
+	if p.excluded > 0 {
+		return
+	}
+	words := textWords(lx.text)
+	for _, token := range tokens {
+		if token.Position < 0 || token.Position >= len(words) {
+			continue
+		}
+		w := words[token.Position]
+		p.sources = append(p.sources, sourceWord{
+			word:  token.Word,
+			start: lx.start + w.start,
+			end:   lx.start + w.end,
+		})
+	}
+}
+
+// span is a matched range of word positions, both ends included.
+type span struct {
+	from, to int
+}
+
+type hit struct {
+	score float64
+	spans []span
+}
+
+type matches map[int]*hit
+
+// postingList is the postings of a word: IDs of their comics as a bitmap,
+// boolean operators work on the bitmaps, and the rest of every posting
+// in the order of the bitmap.
+type postingList struct {
+	ids   *roaring.Bitmap
+	terms []docTerm
+}
+
+func newPostingList(postings []Posting) postingList {
+	postings = slices.SortedFunc(slices.Values(postings), func(a, b Posting) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	postings = slices.CompactFunc(postings, func(a, b Posting) bool {
+		return a.ID == b.ID
+	})
+
+	ids := roaring.New()
+	terms := make([]docTerm, 0, len(postings))
+	for _, p := range postings {
+		ids.Add(uint32(p.ID))
+		terms = append(terms, docTerm{
+			freq:      int32(p.Freq),
+			length:    int32(p.Len),
+			positions: p.Positions,
+			fields:    p.Fields,
+		})
+	}
+	return postingList{ids: ids, terms: terms}
+}
+
+// docFreq is the number of comics with the word.
+func (l postingList) docFreq() int {
+	return len(l.terms)
+}
+
+// posting returns the posting of the word in a comic.
+func (l postingList) posting(id int) (Posting, bool) {
+	if l.ids == nil || !l.ids.Contains(uint32(id)) {
+		return Posting{}, false
+	}
+	return l.at(int(l.ids.Rank(uint32(id)))-1, id), true
+}
+
+// at returns the posting of the k-th comic of the list. Positions and
+// fields are shared, they must not be modified.
+func (l postingList) at(k, id int) Posting {
+	term := l.terms[k]
+	return Posting{
+		ID:        id,
+		Freq:      int(term.freq),
+		Len:       int(term.length),
+		Positions: term.positions,
+		Fields:    term.fields,
+	}
+}
+
+// each calls fn with the postings of comics in within, of all comics
+// when within is nil, in ID order.
+func (l postingList) each(within *roaring.Bitmap, fn func(Posting)) {
+	if l.ids == nil {
+		return
+	}
+	if within == nil {
+		k := 0
+		for it := l.ids.Iterator(); it.HasNext(); k++ {
+			fn(l.at(k, int(it.Next())))
+		}
+		return
+	}
+	for it := roaring.And(l.ids, within).Iterator(); it.HasNext(); {
+		id := it.Next()
+		fn(l.at(int(l.ids.Rank(id))-1, int(id)))
+	}
+}
+
+// postingSet holds the postings of every word a query needs.
+type postingSet map[string]postingList
+
+// ids returns comics containing the word, the bitmap must not be modified.
+func (s postingSet) ids(word string) *roaring.Bitmap {
+	if list, ok := s[word]; ok && list.ids != nil {
+		return list.ids
+	}
+	return roaring.New()
+}
+
+// inField keeps only occurrences of the words in the field.
+func (s postingSet) inField(field Field, words []string) postingSet {
+	result := make(postingSet, len(words))
+	for _, word := range words {
+		var postings []Posting
+		s[word].each(nil, func(p Posting) {
+			var in Posting
+			for i, f := range p.Fields {
+				if f == field {
+					in.Positions = append(in.Positions, p.Positions[i])
+					in.Fields = append(in.Fields, f)
+				}
+			}
+			if len(in.Positions) == 0 {
+				return
+			}
+			in.ID, in.Freq, in.Len = p.ID, len(in.Positions), p.Len
+			postings = append(postings, in)
+		})
+		result[word] = newPostingList(postings)
+	}
+	return result
+}
+
+type queryNode interface {
+	words() []string
+	// docs returns a new bitmap of exactly the comics matching the node.
+	docs(postings postingSet) *roaring.Bitmap
+	// eval scores the matching comics, only those in within when it is set.
+	eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches
+	String() string
+}
+
+// hitsIDs collects comic IDs of evaluated matches.
+func hitsIDs(found matches) *roaring.Bitmap {
+	ids := roaring.New()
+	for id := range found {
+		ids.Add(uint32(id))
+	}
+	return ids
+}
+
+type termNode struct {
+	word string
+}
+
+func (n termNode) words() []string {
+	return []string{n.word}
+}
+
+func (n termNode) docs(postings postingSet) *roaring.Bitmap {
+	return postings.ids(n.word).Clone()
+}
+
+func (n termNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	list := postings[n.word]
+	df := list.docFreq()
+	result := make(matches)
+	list.each(within, func(p Posting) {
+		h := &hit{score: bm25(p, df, stats)}
+		for _, pos := range p.Positions {
+			h.spans = append(h.spans, span{from: pos, to: pos})
+		}
+		result[p.ID] = h
+	})
+	return result
+}
+
+func (n termNode) String() string {
+	return n.word
+}
+
+type phraseNode struct {
+	tokens []Token
+}
+
+func (n phraseNode) words() []string {
+	words := make([]string, 0, len(n.tokens))
+	for _, token := range n.tokens {
+		words = append(words, token.Word)
+	}
+	return words
+}
+
+func (n phraseNode) docs(postings postingSet) *roaring.Bitmap {
+	return hitsIDs(n.eval(postings, CorpusStats{}, nil))
+}
+
+func (n phraseNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	// only comics with every word of the phrase are checked for positions
+	candidates := postings.ids(n.tokens[0].Word).Clone()
+	for _, token := range n.tokens[1:] {
+		candidates.And(postings.ids(token.Word))
+	}
+	if within != nil {
+		candidates.And(within)
+	}
+
+	// per word: comic id -> posting
+	byID := make([]map[int]Posting, len(n.tokens))
+	for i, token := range n.tokens {
+		byID[i] = make(map[int]Posting, candidates.GetCardinality())
+		postings[token.Word].each(candidates, func(p Posting) {
+			byID[i][p.ID] = p
+		})
+	}
+
+	first := n.tokens[0].Position
+	length := n.tokens[len(n.tokens)-1].Position - first
+
+	result := make(matches)
+	for id, head := range byID[0] {
+		var spans []span
+		for _, start := range head.Positions {
+			found := true
+			for i := 1; i < len(n.tokens) && found; i++ {
+				found = slices.Contains(byID[i][id].Positions, start+n.tokens[i].Position-first)
+			}
+			if found {
+				spans = append(spans, span{from: start, to: start + length})
+			}
+		}
+		if len(spans) == 0 {
+			continue
+		}
+
+		h := &hit{spans: spans}
+		for i, token := range n.tokens {
+			h.score += bm25(byID[i][id], postings[token.Word].docFreq(), stats)
+		}
+		result[id] = h
+	}
+	return result
+}
+
+func (n phraseNode) String() string {
+	return `"` + strings.Join(n.words(), " ") + `"`
+}
+
+type nearNode struct {
+	left, right queryNode
+	distance    int
+}
+
+func (n *nearNode) words() []string {
+	return append(n.left.words(), n.right.words()...)
+}
+
+func (n *nearNode) docs(postings postingSet) *roaring.Bitmap {
+	return hitsIDs(n.eval(postings, CorpusStats{}, nil))
+}
+
+func (n *nearNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	candidates := roaring.And(n.left.docs(postings), n.right.docs(postings))
+	if within != nil {
+		candidates.And(within)
+	}
+	left := n.left.eval(postings, stats, candidates)
+	right := n.right.eval(postings, stats, candidates)
+
+	result := make(matches)
+	for id, l := range left {
+		r, ok := right[id]
+		if !ok {
+			continue
+		}
+		if spans := nearSpans(l.spans, r.spans, n.distance); len(spans) > 0 {
+			result[id] = &hit{score: l.score + r.score, spans: spans}
+		}
+	}
+	return result
+}
+
+// nearSpans joins every span of either side with the nearest span of the
+// other side within distance. Joining all pairs would multiply spans with
+// every nested NEAR in a comic with repeated words.
+func nearSpans(left, right []span, distance int) []span {
+	var spans []span
+	join := func(from, to []span) {
+		for _, a := range from {
+			var nearest span
+			best := distance + 1
+			for _, b := range to {
+				if gap := max(b.from-a.to, a.from-b.to); gap < best {
+					nearest, best = span{from: min(a.from, b.from), to: max(a.to, b.to)}, gap
+				}
+			}
+			if best <= distance {
+				spans = append(spans, nearest)
+			}
+		}
+	}
+	join(left, right)
+	join(right, left)
+
+	slices.SortFunc(spans, func(a, b span) int {
+		if c := cmp.Compare(a.from, b.from); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.to, b.to)
+	})
+	return slices.Compact(spans)
+}
+
+func (n *nearNode) String() string {
+	return fmt.Sprintf("%s %s%d %s", group(n.left), nearPrefix, n.distance, group(n.right))
+}
+
+type boolNode struct {
+	must    []queryNode
+	should  []queryNode
+	mustNot []queryNode
+	// lastShould is set while parsing when the latest part was optional
+	lastShould bool
+}
+
+// simplify returns the plainest node equal to the sequence.
+func (n *boolNode) simplify() (queryNode, error) {
+	switch {
+	case len(n.must) == 0 && len(n.should) == 0 && len(n.mustNot) == 0:
+		return nil, nil
+	case len(n.must) == 0 && len(n.should) == 0:
+		return nil, fmt.Errorf("%w: only excluded words in a query", ErrBadArguments)
+	case len(n.must) == 0 && len(n.mustNot) == 0:
+		return newOrNode(n.should), nil
+	}
+	return n, nil
+}
+
+func (n *boolNode) words() []string {
+	var words []string
+	for _, list := range [][]queryNode{n.must, n.should, n.mustNot} {
+		for _, child := range list {
+			words = append(words, child.words()...)
+		}
+	}
+	return words
+}
+
+// docs intersects required parts or joins optional ones when nothing
+// is required, then removes excluded comics.
+func (n *boolNode) docs(postings postingSet) *roaring.Bitmap {
+	var result *roaring.Bitmap
+	if len(n.must) > 0 {
+		result = n.must[0].docs(postings)
+		for _, child := range n.must[1:] {
+			result.And(child.docs(postings))
+		}
+	} else {
+		result = roaring.New()
+		for _, child := range n.should {
+			result.Or(child.docs(postings))
+		}
+	}
+
+	for _, child := range n.mustNot {
+		result.AndNot(child.docs(postings))
+	}
+	return result
+}
+
+// eval scores only comics matched by docs, so every part adds its score
+// to them without intersecting or excluding hits.
+func (n *boolNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	candidates := n.docs(postings)
+	if within != nil {
+		candidates.And(within)
+	}
+
+	result := make(matches, candidates.GetCardinality())
+	for _, child := range append(slices.Clone(n.must), n.should...) {
+		union(result, child.eval(postings, stats, candidates))
+	}
+	return result
+}
+
+func (n *boolNode) String() string {
+	var parts []string
+	for _, child := range n.must {
+		parts = append(parts, "+"+group(child))
+	}
+	for _, child := range n.should {
+		parts = append(parts, group(child))
+	}
+	for _, child := range n.mustNot {
+		parts = append(parts, "-"+group(child))
+	}
+	return strings.Join(parts, " ")
+}
+
+// fieldNode matches its child only with words of one field.
+type fieldNode struct {
+	field Field
+	child queryNode
+}
+
+func (n *fieldNode) words() []string {
+	return n.child.words()
+}
+
+func (n *fieldNode) docs(postings postingSet) *roaring.Bitmap {
+	return n.child.docs(postings.inField(n.field, n.words()))
+}
+
+func (n *fieldNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	return n.child.eval(postings.inField(n.field, n.words()), stats, within)
+}
+
+func (n *fieldNode) String() string {
+	return string(n.field) + ":" + group(n.child)
+}
+
+// group wraps composite nodes in parentheses when printed inside others.
+func group(n queryNode) string {
+	switch n.(type) {
+	case *orNode, *boolNode, *nearNode:
+		return "(" + n.String() + ")"
+	}
+	return n.String()
+}
+
+// union adds every hit of src to dst.
+func union(dst, src matches) {
+	for id, h := range src {
+		if acc, ok := dst[id]; ok {
+			acc.score += h.score
+			acc.spans = append(acc.spans, h.spans...)
+			continue
+		}
+		dst[id] = h
+	}
+}
+
+type orNode struct {
+	children []queryNode
+}
+
+// newOrNode drops repeated alternatives so a word mentioned twice
+// in a query is not scored twice.
+func newOrNode(children []queryNode) queryNode {
+	unique := make([]queryNode, 0, len(children))
+	seen := make(map[string]bool, len(children))
+	for _, child := range children {
+		if !seen[child.String()] {
+			seen[child.String()] = true
+			unique = append(unique, child)
+		}
+	}
+
+	switch len(unique) {
+	case 0:
+		return nil
+	case 1:
+		return unique[0]
+	}
+	return &orNode{children: unique}
+}
+
+func (n *orNode) words() []string {
+	var words []string
+	for _, child := range n.children {
+		words = append(words, child.words()...)
+	}
+	return words
+}
+
+func (n *orNode) docs(postings postingSet) *roaring.Bitmap {
+	result := roaring.New()
+	for _, child := range n.children {
+		result.Or(child.docs(postings))
+	}
+	return result
+}
+
+func (n *orNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	result := make(matches)
+	for _, child := range n.children {
+		union(result, child.eval(postings, stats, within))
+	}
+	return result
+}
+
+func (n *orNode) String() string {
+	parts := make([]string, 0, len(n.children))
+	for _, child := range n.children {
+		if _, ok := child.(*boolNode); ok {
+			parts = append(parts, group(child))
+			continue
+		}
+		parts = append(parts, child.String())
+	}
+	return strings.Join(parts, " OR ")
+}
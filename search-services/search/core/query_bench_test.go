@@ -0,0 +1,245 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapEval evaluates boolean operators the way it was done before bitmaps:
+// every part is scored for all its comics, then hit maps are intersected,
+// merged and filtered.
+func mapEval(node queryNode, postings postingSet, stats CorpusStats) matches {
+	switch n := node.(type) {
+	case *boolNode:
+		var result matches
+		if len(n.must) > 0 {
+			result = mapEval(n.must[0], postings, stats)
+			for _, child := range n.must[1:] {
+				result = mapIntersect(result, mapEval(child, postings, stats))
+			}
+			for _, child := range n.should {
+				mapBoost(result, mapEval(child, postings, stats))
+			}
+		} else {
+			result = make(matches)
+			for _, child := range n.should {
+				union(result, mapEval(child, postings, stats))
+			}
+		}
+		for _, child := range n.mustNot {
+			for id := range mapEval(child, postings, stats) {
+				delete(result, id)
+			}
+		}
+		return result
+	case *orNode:
+		result := make(matches)
+		for _, child := range n.children {
+			union(result, mapEval(child, postings, stats))
+		}
+		return result
+	}
+	return node.eval(postings, stats, nil)
+}
+
+func mapBoost(dst, src matches) {
+	for id, h := range src {
+		if acc, ok := dst[id]; ok {
+			acc.score += h.score
+		}
+	}
+}
+
+func mapIntersect(a, b matches) matches {
+	result := make(matches, min(len(a), len(b)))
+	for id, h := range a {
+		if other, ok := b[id]; ok {
+			h.score += other.score
+			result[id] = h
+		}
+	}
+	return result
+}
+
+// benchCorpus makes comics where word i occurs in about 1/(i+1)
+// of them, so low numbered words are common and high ones are rare.
+func benchCorpus(comics, words int) []Comics {
+	rnd := rand.New(rand.NewSource(1))
+	corpus := make([]Comics, 0, comics)
+	for id := 1; id <= comics; id++ {
+		c := Comics{ID: id}
+		for w := 0; w < words; w++ {
+			if rnd.Intn(w+1) == 0 {
+				c.Tokens = append(c.Tokens, Token{Word: fmt.Sprintf("w%d", w), Position: w})
+			}
+		}
+		corpus = append(corpus, c)
+	}
+	return corpus
+}
+
+func benchIndex(comics, words int) *Index {
+	index := NewIndex()
+	index.Update(benchCorpus(comics, words))
+	return index
+}
+
+// copyLookup returns postings of a word copied out of the index, as
+// lookup did before postings were read from the index in place.
+func copyLookup(index *Index, word string) postingList {
+	var postings []Posting
+	index.lookup(word).each(nil, func(p Posting) {
+		postings = append(postings, p)
+	})
+	return newPostingList(postings)
+}
+
+// lookupAll collects postings of every query word.
+func lookupAll(root queryNode, lookup func(word string) postingList) postingSet {
+	postings := make(postingSet)
+	for _, word := range root.words() {
+		postings[word] = lookup(word)
+	}
+	return postings
+}
+
+var benchQueries = []string{
+	"+w0 +w1 +w2",
+	"w0 AND w30",
+	"w0 OR w1 OR w2",
+	"w0 -w1 -w2",
+	"(w1 OR w2) AND NOT w3",
+}
+
+func TestBitmapEvalMatchesMapEval(t *testing.T) {
+	index := benchIndex(2000, 40)
+	stats := index.Stats()
+	for _, query := range benchQueries {
+		t.Run(query, func(t *testing.T) {
+			root, err := parseQuery(context.Background(), query, splitNorm)
+			require.NoError(t, err)
+
+			copied := lookupAll(root, func(word string) postingList { return copyLookup(index, word) })
+			want := mapEval(root, copied, stats)
+			got := root.eval(lookupAll(root, index.lookup), stats, nil)
+			require.Len(t, got, len(want))
+			for id, h := range want {
+				require.Contains(t, got, id)
+				assert.InDelta(t, h.score, got[id].score, 1e-9)
+			}
+		})
+	}
+}
+
+// BenchmarkQueryEval looks up the query words and evaluates the query:
+// index reads postings in place, copy copies them out of the index first
+// and map also evaluates boolean operators over hit maps instead of
+// bitmaps.
+func BenchmarkQueryEval(b *testing.B) {
+	index := benchIndex(20000, 40)
+	stats := index.Stats()
+	copied := func(word string) postingList { return copyLookup(index, word) }
+	for _, query := range benchQueries {
+		root, err := parseQuery(context.Background(), query, splitNorm)
+		require.NoError(b, err)
+
+		b.Run("index/"+query, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				root.eval(lookupAll(root, index.lookup), stats, nil)
+			}
+		})
+		b.Run("copy/"+query, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				root.eval(lookupAll(root, copied), stats, nil)
+			}
+		})
+		b.Run("map/"+query, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				mapEval(root, lookupAll(root, copied), stats)
+			}
+		})
+	}
+}
+
+// postingsIndex is the layout the index had before: postings of every
+// word next to its bitmap.
+type postingsIndex struct {
+	postings map[string][]Posting
+	ids      map[string]*roaring.Bitmap
+	docs     map[int][]string
+}
+
+func newPostingsIndex(corpus []Comics) *postingsIndex {
+	index := &postingsIndex{
+		postings: make(map[string][]Posting),
+		ids:      make(map[string]*roaring.Bitmap),
+		docs:     make(map[int][]string),
+	}
+	for _, c := range corpus {
+		positions := make(map[string][]int)
+		fields := make(map[string][]Field)
+		for _, token := range c.Tokens {
+			positions[token.Word] = append(positions[token.Word], token.Position)
+			fields[token.Word] = append(fields[token.Word], token.Field)
+		}
+		for word, pos := range positions {
+			index.postings[word] = append(index.postings[word], Posting{
+				ID: c.ID, Freq: len(pos), Len: len(c.Tokens), Positions: pos, Fields: fields[word],
+			})
+			if index.ids[word] == nil {
+				index.ids[word] = roaring.New()
+			}
+			index.ids[word].Add(uint32(c.ID))
+			index.docs[c.ID] = append(index.docs[c.ID], word)
+		}
+	}
+	return index
+}
+
+// BenchmarkIndexSize reports the heap taken by the index and by the
+// layout it had before.
+func BenchmarkIndexSize(b *testing.B) {
+	corpus := benchCorpus(20000, 40)
+	for _, layout := range []struct {
+		name  string
+		build func() any
+	}{
+		{"index", func() any {
+			index := NewIndex()
+			index.Update(corpus)
+			return index
+		}},
+		{"postings", func() any { return newPostingsIndex(corpus) }},
+	} {
+		b.Run(layout.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var size uint64
+			for range b.N {
+				size = heapGrowth(layout.build)
+			}
+			b.ReportMetric(float64(size), "heap-B")
+		})
+	}
+}
+
+// heapGrowth returns how much the heap grows by the value build returns.
+func heapGrowth(build func() any) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	value := build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(value)
+	return after.HeapAlloc - before.HeapAlloc
+}
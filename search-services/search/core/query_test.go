@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// splitNorm imitates the words service: words are runs of letters and
+// digits in lower case, "the" is a stop word, positions count every word.
+func splitNorm(_ context.Context, phrase string) ([]Token, error) {
+	var tokens []Token
+	for i, word := range strings.FieldsFunc(strings.ToLower(phrase), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if word != "the" {
+			tokens = append(tokens, Token{Word: word, Position: i})
+		}
+	}
+	return tokens, nil
+}
+
+func TestParseQueryNormalizesOnce(t *testing.T) {
+	var calls []string
+	norm := func(ctx context.Context, phrase string) ([]Token, error) {
+		calls = append(calls, phrase)
+		return splitNorm(ctx, phrase)
+	}
+
+	root, err := parseQuery(context.Background(), `cat "little the bobby" NEAR/3 tables -title:dog (fish OR "")`, norm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`cat little the bobby tables dog fish`}, calls)
+	assert.Equal(t, `cat ("little bobby" NEAR/3 tables) fish -title:dog`, root.String())
+	phrase := root.(*boolNode).should[1].(*nearNode).left.(phraseNode)
+	// positions start at the phrase
+	assert.Equal(t, []Token{{Word: "little", Position: 0}, {Word: "bobby", Position: 2}}, phrase.tokens)
+
+	calls = nil
+	root, err = parseQuery(context.Background(), `( "" )`, norm)
+	require.NoError(t, err)
+	assert.Nil(t, root)
+	assert.Empty(t, calls)
+}
+
+func TestParseQuerySources(t *testing.T) {
+	query := `Cats NOT (dog fish) title:"Little the Bobby" -tables xkcd.com`
+	_, sources, err := parseQuerySources(context.Background(), query, splitNorm)
+	require.NoError(t, err)
+	assert.Equal(t, []sourceWord{
+		{word: "cats", start: 0, end: 4},
+		{word: "little", start: 27, end: 33},
+		{word: "bobby", start: 38, end: 43},
+		{word: "xkcd", start: 53, end: 57},
+		{word: "com", start: 58, end: 61},
+	}, sources)
+
+	replaced := replaceSources(query, sources, map[string]string{"cats": "cat", "bobby": "bobbi", "com": "org"})
+	assert.Equal(t, `cat NOT (dog fish) title:"Little the bobbi" -tables xkcd.org`, replaced)
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		`"little bobby`,
+		`NEAR/2 cat`,
+		`cat NEAR/2`,
+		`cat NEAR/x dog`,
+		`cat NEAR/0 dog`,
+		`cat NEAR/-1 dog`,
+		`cat NEAR/51 dog`,
+		`cat NEAR/99999999999999999999 dog`,
+		`(cat OR dog`,
+		`cat OR dog)`,
+		`OR cat`,
+		`cat AND`,
+		`-cat -dog`,
+		`cat ()`,
+		`cat NEAR/2 +dog`,
+		`title:`,
+		`title: cat`,
+		`(title:)`,
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, err := parseQuery(context.Background(), query, splitNorm)
+			assert.True(t, errors.Is(err, ErrBadArguments), "got %v", err)
+		})
+	}
+}
+
+func TestParseQueryString(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: `physics -phone (cat OR dog)`, want: `physics (cat OR dog) -phone`},
+		{query: `cat AND dog`, want: `+cat +dog`},
+		{query: `"the cat" NEAR/2 dog`, want: `cat NEAR/2 dog`},
+		{query: `cat dog OR +fish bird`, want: `cat OR dog OR (+fish bird)`},
+		{query: `title:cat -alt:(dog OR fish)`, want: `title:cat -alt:(dog OR fish)`},
+		{query: `transcript:"the little bobby"`, want: `transcript:"little bobby"`},
+		{query: `http://xkcd.com`, want: `http OR xkcd OR com`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			root, err := parseQuery(context.Background(), tt.query, splitNorm)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, root.String())
+		})
+	}
+}
+
+func TestQueryEval(t *testing.T) {
+	// 1: "little bobby tables"
+	// 2: "bobby little tables"
+	// 3: "little of the bobby"
+	postings := newPostingSet(map[string][]Posting{
+		"little": {{ID: 1, Freq: 1, Len: 3, Positions: []int{0}}, {ID: 2, Freq: 1, Len: 3, Positions: []int{1}}, {ID: 3, Freq: 1, Len: 4, Positions: []int{0}}},
+		"bobby":  {{ID: 1, Freq: 1, Len: 3, Positions: []int{1}}, {ID: 2, Freq: 1, Len: 3, Positions: []int{0}}, {ID: 3, Freq: 1, Len: 4, Positions: []int{3}}},
+		"tables": {{ID: 1, Freq: 1, Len: 3, Positions: []int{2}}, {ID: 2, Freq: 1, Len: 3, Positions: []int{2}}},
+		"of":     {{ID: 3, Freq: 1, Len: 4, Positions: []int{1}}},
+	})
+	stats := CorpusStats{Docs: 3, AvgLen: 3}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{query: `little bobby`, want: []int{1, 2, 3}},
+		{query: `"little bobby tables"`, want: []int{1}},
+		{query: `"bobby little"`, want: []int{2}},
+		{query: `"little of the bobby"`, want: []int{3}},
+		{query: `little NEAR/1 bobby`, want: []int{1, 2}},
+		{query: `little NEAR/3 bobby`, want: []int{1, 2, 3}},
+		{query: `"little bobby" NEAR/1 tables`, want: []int{1}},
+		{query: `the NEAR/1 tables`, want: []int{1, 2}},
+		{query: `little -tables`, want: []int{3}},
+		{query: `+tables of`, want: []int{1, 2}},
+		{query: `of OR tables`, want: []int{1, 2, 3}},
+		{query: `little AND NOT (tables OR of)`, want: []int{}},
+		{query: `bobby -(of OR "little bobby")`, want: []int{2}},
+		{query: `(of OR tables) NEAR/1 bobby`, want: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			root, err := parseQuery(context.Background(), tt.query, splitNorm)
+			require.NoError(t, err)
+			ids := make([]int, 0)
+			for id := range root.eval(postings, stats, nil) {
+				ids = append(ids, id)
+			}
+			slices.Sort(ids)
+			assert.Equal(t, tt.want, ids)
+
+			docs := make([]int, 0)
+			for _, id := range root.docs(postings).ToArray() {
+				docs = append(docs, int(id))
+			}
+			assert.Equal(t, tt.want, docs)
+		})
+	}
+}
+
+func TestNestedNearSpans(t *testing.T) {
+	// "a b c" repeated, every NEAR operand has a span at every repeat
+	const repeats = 300
+	var a, b, c []int
+	for i := range repeats {
+		a = append(a, 3*i)
+		b = append(b, 3*i+1)
+		c = append(c, 3*i+2)
+	}
+	postings := newPostingSet(map[string][]Posting{
+		"a": {{ID: 1, Freq: repeats, Len: 3 * repeats, Positions: a}},
+		"b": {{ID: 1, Freq: repeats, Len: 3 * repeats, Positions: b}},
+		"c": {{ID: 1, Freq: repeats, Len: 3 * repeats, Positions: c}},
+	})
+
+	root, err := parseQuery(context.Background(), `((a NEAR/10 b) NEAR/10 c) NEAR/10 (a NEAR/10 "b c")`, splitNorm)
+	require.NoError(t, err)
+	found := root.eval(postings, CorpusStats{Docs: 1, AvgLen: 3 * repeats}, nil)
+	require.Contains(t, found, 1)
+	assert.LessOrEqual(t, len(found[1].spans), 2*3*repeats)
+
+	// the nearest spans of both sides
+	assert.Equal(t, []span{{from: 0, to: 2}, {from: 1, to: 3}}, nearSpans(
+		[]span{{from: 0, to: 0}, {from: 3, to: 3}},
+		[]span{{from: 1, to: 2}},
+		5,
+	))
+}
+
+func TestFieldQueryEval(t *testing.T) {
+	// 1: title "little bobby", transcript "tables"
+	// 2: title "tables", alt "little bobby"
+	postings := newPostingSet(map[string][]Posting{
+		"little": {
+			{ID: 1, Freq: 1, Len: 3, Positions: []int{0}, Fields: []Field{FieldTitle}},
+			{ID: 2, Freq: 1, Len: 3, Positions: []int{101}, Fields: []Field{FieldAlt}},
+		},
+		"bobby": {
+			{ID: 1, Freq: 1, Len: 3, Positions: []int{1}, Fields: []Field{FieldTitle}},
+			{ID: 2, Freq: 1, Len: 3, Positions: []int{102}, Fields: []Field{FieldAlt}},
+		},
+		"tables": {
+			{ID: 1, Freq: 1, Len: 3, Positions: []int{102}, Fields: []Field{FieldTranscript}},
+			{ID: 2, Freq: 1, Len: 3, Positions: []int{0}, Fields: []Field{FieldTitle}},
+		},
+	})
+	stats := CorpusStats{Docs: 2, AvgLen: 3}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{query: `title:tables`, want: []int{2}},
+		{query: `title:"little bobby"`, want: []int{1}},
+		{query: `alt:(little OR tables)`, want: []int{2}},
+		{query: `tables -title:little`, want: []int{2}},
+		{query: `transcript:little`, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			root, err := parseQuery(context.Background(), tt.query, splitNorm)
+			require.NoError(t, err)
+			ids := make([]int, 0)
+			for id := range root.eval(postings, stats, nil) {
+				ids = append(ids, id)
+			}
+			slices.Sort(ids)
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func newPostingSet(words map[string][]Posting) postingSet {
+	postings := make(postingSet, len(words))
+	for word, list := range words {
+		postings[word] = newPostingList(list)
+	}
+	return postings
+}
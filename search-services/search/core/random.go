@@ -0,0 +1,50 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// Random returns a comic picked uniformly among fetched comics, or among
+// the comics matching keywords if they are given. Matching comics are
+// found like Search finds them. ErrNotFound means nothing to pick from.
+func (s *Service) Random(ctx context.Context, keywords string) (Comics, error) {
+	if strings.TrimSpace(keywords) == "" {
+		id, err := s.db.RandomID(ctx)
+		if err != nil {
+			return Comics{}, fmt.Errorf("failed to pick a random comic: %w", err)
+		}
+		return s.GetComic(ctx, id)
+	}
+
+	mode, err := s.backendMode(ctx)
+	if err != nil {
+		return Comics{}, err
+	}
+	parsed, err := s.parse(ctx, keywords)
+	if err != nil {
+		return Comics{}, err
+	}
+	page, err := s.rankPage(ctx, SearchQuery{Keywords: keywords}, parsed, mode)
+	if err != nil {
+		return Comics{}, err
+	}
+	if len(page.ranked) == 0 {
+		return Comics{}, fmt.Errorf("%w: no comics match %q", ErrNotFound, keywords)
+	}
+	return s.GetComic(ctx, page.ranked[rand.IntN(len(page.ranked))].ID)
+}
+
+// Latest returns the comic with the largest ID.
+func (s *Service) Latest(ctx context.Context) (Comics, error) {
+	id, err := s.db.MaxId(ctx)
+	if err != nil {
+		return Comics{}, fmt.Errorf("failed to get the latest comic id: %w", err)
+	}
+	if id == 0 {
+		return Comics{}, fmt.Errorf("%w: no comics yet", ErrNotFound)
+	}
+	return s.GetComic(ctx, id)
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomAndLatest(t *testing.T) {
+	db := &memoryDB{t: t, searchable: true}
+	service := newTestService(t, testOptions{db: db})
+
+	_, err := service.Random(context.Background(), "")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = service.Latest(context.Background())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	db.put(3, "cat on a keyboard")
+	db.put(7, "dog in a box")
+	db.put(5, "cat and dog")
+
+	seen := make(map[int]bool)
+	for range 100 {
+		comics, err := service.Random(context.Background(), "cat")
+		require.NoError(t, err)
+		seen[comics.ID] = true
+	}
+	assert.Equal(t, map[int]bool{3: true, 5: true}, seen)
+
+	comics, err := service.Random(context.Background(), "  ")
+	require.NoError(t, err)
+	assert.Contains(t, []int{3, 5, 7}, comics.ID)
+
+	_, err = service.Random(context.Background(), "fish")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = service.Random(context.Background(), "(cat")
+	assert.ErrorIs(t, err, ErrBadArguments)
+
+	comics, err = service.Latest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 7, comics.ID)
+}
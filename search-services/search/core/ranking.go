@@ -0,0 +1,60 @@
+package core
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Okapi BM25 parameters: k1 controls term frequency saturation,
+// b controls how strongly long comics are penalised.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+type scoredID struct {
+	ID    int
+	Score float64
+}
+
+func idf(docs, df int) float64 {
+	return math.Log(1 + (float64(docs)-float64(df)+0.5)/(float64(df)+0.5))
+}
+
+// termFreq counts occurrences of a word weighted by their fields.
+func termFreq(p Posting, boosts FieldBoosts) float64 {
+	if len(p.Fields) == 0 {
+		return float64(p.Freq)
+	}
+	var tf float64
+	for _, field := range p.Fields {
+		tf += boosts.weight(field)
+	}
+	return tf
+}
+
+func bm25(p Posting, df int, stats CorpusStats) float64 {
+	tf := termFreq(p, stats.Boosts)
+	norm := 1.0
+	if stats.AvgLen > 0 {
+		norm = 1 - bm25B + bm25B*float64(p.Len)/stats.AvgLen
+	}
+	return idf(stats.Docs, df) * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+}
+
+// prioritySorting orders matched comics by descending relevance.
+func prioritySorting(found matches) []scoredID {
+	ranked := make([]scoredID, 0, len(found))
+	for id, h := range found {
+		ranked = append(ranked, scoredID{ID: id, Score: h.score})
+	}
+	slices.SortFunc(ranked, func(a, b scoredID) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return ranked
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rankedIDs(stats CorpusStats, terms ...[]Posting) []int {
+	postings := make(postingSet, len(terms))
+	nodes := make([]queryNode, 0, len(terms))
+	for i, list := range terms {
+		word := fmt.Sprintf("word%d", i)
+		postings[word] = newPostingList(list)
+		nodes = append(nodes, termNode{word: word})
+	}
+
+	ids := make([]int, 0)
+	for _, r := range prioritySorting(newOrNode(nodes).eval(postings, stats, nil)) {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestPrioritySorting(t *testing.T) {
+	stats := CorpusStats{Docs: 100, AvgLen: 50}
+
+	tests := []struct {
+		name  string
+		terms [][]Posting
+		want  []int
+	}{
+		{
+			name: "short comic beats long transcript",
+			terms: [][]Posting{{
+				{ID: 1, Freq: 1, Len: 400},
+				{ID: 2, Freq: 1, Len: 5},
+			}},
+			want: []int{2, 1},
+		},
+		{
+			name: "frequent word beats single mention",
+			terms: [][]Posting{{
+				{ID: 1, Freq: 1, Len: 50},
+				{ID: 2, Freq: 4, Len: 50},
+			}},
+			want: []int{2, 1},
+		},
+		{
+			name: "rare word beats common word",
+			terms: [][]Posting{
+				{{ID: 1, Freq: 1, Len: 50}, {ID: 3, Freq: 1, Len: 50}, {ID: 4, Freq: 1, Len: 50}},
+				{{ID: 2, Freq: 1, Len: 50}},
+			},
+			want: []int{2, 1, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rankedIDs(stats, tt.terms...))
+		})
+	}
+}
+
+func TestFieldBoosts(t *testing.T) {
+	terms := []Posting{
+		{ID: 1, Freq: 1, Len: 50, Fields: []Field{FieldTranscript}},
+		{ID: 2, Freq: 1, Len: 50, Fields: []Field{FieldTitle}},
+		{ID: 3, Freq: 1, Len: 50, Fields: []Field{FieldAlt}},
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, rankedIDs(CorpusStats{Docs: 100, AvgLen: 50}, terms))
+	boosts := FieldBoosts{FieldTitle: 3, FieldAlt: 2}
+	assert.Equal(t, []int{2, 3, 1}, rankedIDs(CorpusStats{Docs: 100, AvgLen: 50, Boosts: boosts}, terms))
+}
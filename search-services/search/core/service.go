@@ -1,92 +1,123 @@
 package core
 
 import (
-	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
 	"maps"
 	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const numWorkers = 3
 
 type Service struct {
-	log   *slog.Logger
-	db    DB
-	words Words
-	index *Index
+	log       *slog.Logger
+	db        DB
+	words     Words
+	snapshots Snapshots
+	backend   string
+	boosts    FieldBoosts
+	cache     *QueryCache
+	// index is swapped as a whole after a full rebuild
+	index    atomic.Pointer[Index]
+	statusMu sync.RWMutex
+	status   IndexStatus
+	// synonyms are read by searches without locking
+	synonymStore  SynonymStore
+	synonymsMu    sync.Mutex
+	synonymGroups []SynonymGroup
+	synonyms      atomic.Pointer[synonymDict]
 }
 
-func NewService(log *slog.Logger, db DB, words Words) (*Service, error) {
-	return &Service{
-		log:   log,
-		db:    db,
-		words: words,
-		index: NewIndex()}, nil
-}
-
-func workerSearch(word string, s *Service, ctx context.Context) ([]int, error) {
-	ids, err := s.db.Search(ctx, word)
-	return ids, err
-}
-func prioritySorting(output <-chan []int) []int {
+// Database search backends.
+const (
+	BackendArray    = "array"
+	BackendFullText = "fts"
+)
 
-	countingIds := make(map[int]int, 0)
-	for result := range output {
-		for _, id := range result {
-			countingIds[id]++
+func NewService(
+	log *slog.Logger, db DB, words Words, snapshots Snapshots, synonyms SynonymStore,
+	backend string, boosts FieldBoosts, cache *QueryCache,
+) (*Service, error) {
+	if backend != BackendArray && backend != BackendFullText {
+		return nil, fmt.Errorf("unknown search backend: %q", backend)
+	}
+	for field, boost := range boosts {
+		if boost <= 0 {
+			return nil, fmt.Errorf("wrong boost of field %s: %v", field, boost)
 		}
 	}
+	s := &Service{
+		log:       log,
+		db:        db,
+		words:     words,
+		snapshots: snapshots,
+		backend:   backend,
+		boosts:    boosts,
+		cache:     cache,
+		status:    IndexStatus{Status: StatusIdle},
+
+		synonymStore: synonyms,
+	}
+	s.index.Store(NewIndex())
+	s.synonyms.Store(&synonymDict{})
+	return s, nil
+}
 
-	sortedIds := slices.SortedFunc(maps.Keys(countingIds), func(a, b int) int {
-		return cmp.Compare(countingIds[b], countingIds[a])
-	})
+type searchFunc func(ctx context.Context, word string) (postingList, error)
 
-	return sortedIds
+func indexSearch(index *Index) searchFunc {
+	return func(_ context.Context, word string) (postingList, error) {
+		return index.lookup(word), nil
+	}
 }
-func (s *Service) Search(ctx context.Context, query SearchQuery) ([]Comics, error) {
-	wordsNorm, err := s.words.Norm(ctx, query.Keywords)
+
+func (s *Service) dbSearch(ctx context.Context, word string) (postingList, error) {
+	postings, err := s.db.Search(ctx, word)
 	if err != nil {
-		return nil, err
+		return postingList{}, err
 	}
+	return newPostingList(postings), nil
+}
 
+// collectPostings looks up postings of every word with a pool of workers.
+func (s *Service) collectPostings(ctx context.Context, words []string, search searchFunc) (postingSet, error) {
 	input := make(chan string)
 	go func() {
-		for _, i := range wordsNorm {
-			input <- i
+		defer close(input)
+		for _, word := range words {
+			select {
+			case input <- word:
+			case <-ctx.Done():
+				return
+			}
 		}
-		close(input)
 	}()
 
-	output := make(chan []int)
-	errChan := make(chan error, numWorkers)
+	type result struct {
+		word     string
+		postings postingList
+	}
+
+	output := make(chan result)
+	errChan := make(chan error, len(words))
 	var wg sync.WaitGroup
 	wg.Add(numWorkers)
 
-	firstErrChan := make(chan error, 1)
-	go func() {
-		for err := range errChan {
-			s.log.Error("couldn't find the comics", "error", err)
-			select {
-			case firstErrChan <- err:
-			default:
-			}
-		}
-		close(firstErrChan)
-	}()
-
 	for i := 0; i < numWorkers; i++ {
 		go func() {
 			defer wg.Done()
 			for word := range input {
-				ids, err := workerSearch(word, s, ctx)
+				postings, err := search(ctx, word)
 				if err != nil {
 					errChan <- fmt.Errorf("error when searching comics by word %s: %w", word, err)
-					return
+					continue
 				}
-				output <- ids
+				output <- result{word: word, postings: postings}
 			}
 		}()
 	}
@@ -95,124 +126,418 @@ func (s *Service) Search(ctx context.Context, query SearchQuery) ([]Comics, erro
 		wg.Wait()
 		close(output)
 		close(errChan)
-
 	}()
 
-	sortedIds := prioritySorting(output)
-
-	var (
-		errGet error
-		count  int
-	)
-	cachedComics := make([]Comics, 0)
-
-	for _, index := range sortedIds {
+	postings := make(postingSet, len(words))
+	for r := range output {
+		postings[r.word] = r.postings
+	}
 
-		var comics Comics
-		comics, errGet = s.db.Get(ctx, index)
-		if errGet != nil {
-			s.log.Error("err get comics", "error", errGet)
-			errGet = fmt.Errorf("failed to get comic from the database %d: %w", index, errGet)
-			break
+	var firstErr error
+	for err := range errChan {
+		s.log.Error("couldn't find the comics", "error", err)
+		if firstErr == nil {
+			firstErr = err
 		}
+	}
+	return postings, firstErr
+}
 
-		cachedComics = append(cachedComics, comics)
-		count++
-		if count == query.Limit {
-			break
-		}
+// fetchComics loads ranked comics in one query, comics removed from
+// the database since ranking are skipped. Snippets show the found words.
+func (s *Service) fetchComics(ctx context.Context, ranked []scoredID, words map[string]bool) ([]Comics, error) {
+	ids := make([]int, 0, len(ranked))
+	for _, r := range ranked {
+		ids = append(ids, r.ID)
+	}
+	if len(ids) == 0 {
+		return make([]Comics, 0), nil
 	}
 
-	select {
-	case err := <-firstErrChan:
-		return cachedComics, err
-	default:
-		return cachedComics, errGet
+	found, err := s.db.GetMany(ctx, ids)
+	if err != nil {
+		s.log.Error("err get comics", "error", err)
+		return make([]Comics, 0), fmt.Errorf("failed to get comics from the database: %w", err)
+	}
+	byID := make(map[int]Comics, len(found))
+	for _, c := range found {
+		byID[c.ID] = c
 	}
 
+	comics := make([]Comics, 0, len(ranked))
+	for _, r := range ranked {
+		c, ok := byID[r.ID]
+		if !ok {
+			continue
+		}
+		c.Score = r.Score
+		c.Snippet = snippet(c, words)
+		comics = append(comics, c)
+	}
+	return comics, nil
 }
-func workerSearchIndex(word string, s *Service) []int {
-	ids := s.index.Get(word)
-	return ids
+
+// rankFunc orders all comics matching a query, the best first.
+type rankFunc func(ctx context.Context, root queryNode, postings postingSet) ([]scoredID, error)
+
+// rankBM25 scores comics by query postings.
+func rankBM25(stats CorpusStats) rankFunc {
+	return func(_ context.Context, root queryNode, postings postingSet) ([]scoredID, error) {
+		return prioritySorting(root.eval(postings, stats, nil)), nil
+	}
 }
-func (s *Service) SearchIndex(ctx context.Context, query SearchQuery) ([]Comics, error) {
-	wordsNorm, err := s.words.Norm(ctx, query.Keywords)
+
+// rankFullText leaves matching and scoring to Postgres full-text search.
+func (s *Service) rankFullText(ctx context.Context, root queryNode, _ postingSet) ([]scoredID, error) {
+	query, err := toTextQuery(root, s.boosts)
 	if err != nil {
 		return nil, err
 	}
+	found, err := s.db.FullText(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed full-text search: %w", err)
+	}
+	ranked := make([]scoredID, 0, len(found))
+	for _, c := range found {
+		ranked = append(ranked, scoredID{ID: c.ID, Score: c.Score})
+	}
+	return ranked, nil
+}
 
-	input := make(chan string)
-	go func() {
-		for _, i := range wordsNorm {
-			input <- i
-		}
-		close(input)
-	}()
+// searchMode is how comics are found and ranked: in the database with
+// one of the backends or in the index.
+type searchMode struct {
+	name   string
+	search searchFunc
+	rank   rankFunc
+	// bm25 is set if rank scores with BM25 and stats,
+	// the stats explain the scores
+	bm25  bool
+	stats CorpusStats
+}
 
-	output := make(chan []int)
-	var wg sync.WaitGroup
-	wg.Add(numWorkers)
+// rankedPage is a page of found comics before they are read from
+// the database.
+type rankedPage struct {
+	result SearchResult
+	ranked []scoredID
+	// words are highlighted in snippets
+	words map[string]bool
+	// explanations by comic ID, only if they were asked for
+	explanations map[int]*Explanation
+}
 
-	for i := 0; i < numWorkers; i++ {
-		go func() {
-			defer wg.Done()
-			for word := range input {
-				ids := workerSearchIndex(word, s)
-				output <- ids
-			}
-		}()
+// withExplanations attaches explanations to the read comics.
+func (p rankedPage) withExplanations(comics []Comics) []Comics {
+	for i := range comics {
+		comics[i].Explain = p.explanations[comics[i].ID]
 	}
+	return comics
+}
 
-	go func() {
-		wg.Wait()
-		close(output)
-	}()
+func (s *Service) search(ctx context.Context, query SearchQuery, parsed parsedQuery, mode searchMode) (SearchResult, error) {
+	page, err := s.rankPage(ctx, query, parsed, mode)
+	if err != nil {
+		return page.result, err
+	}
+	comics, err := s.fetchComics(ctx, page.ranked, page.words)
+	page.result.Comics = page.withExplanations(comics)
+	return page.result, err
+}
+
+// parsedQuery is a query normalized by the words service together with
+// where its words are written.
+type parsedQuery struct {
+	root    queryNode
+	sources []sourceWord
+}
 
-	sortedIds := prioritySorting(output)
+func (s *Service) parse(ctx context.Context, keywords string) (parsedQuery, error) {
+	root, sources, err := parseQuerySources(ctx, keywords, s.words.Norm)
+	return parsedQuery{root: root, sources: sources}, err
+}
 
-	var (
-		errGet error
-		count  int
-	)
-	cachedComics := make([]Comics, 0)
+// rankPage finds the page of comics for the parsed query.
+func (s *Service) rankPage(ctx context.Context, query SearchQuery, parsedQuery parsedQuery, mode searchMode) (rankedPage, error) {
+	root, sources := parsedQuery.root, parsedQuery.sources
+	if root == nil {
+		return rankedPage{}, nil
+	}
 
-	for _, index := range sortedIds {
+	words := slices.Compact(slices.Sorted(slices.Values(root.words())))
+	postings, err := s.collectPostings(ctx, words, mode.search)
+	if err != nil {
+		return rankedPage{result: SearchResult{Query: root.String()}}, err
+	}
 
-		var comics Comics
-		comics, errGet = s.db.Get(ctx, index)
-		if errGet != nil {
-			s.log.Error("err get comics", "error", errGet)
-			errGet = fmt.Errorf("failed to get comic from the database %d: %w", index, errGet)
-			break
+	parsed := root
+	expansions, result := s.expand(root, postings)
+	if len(expansions) > 0 {
+		root = expandTerms(root, expansions)
+	}
+	if synonyms := *s.synonyms.Load(); len(synonyms) > 0 {
+		root = withSynonyms(root, synonyms)
+	}
+	failed := rankedPage{result: SearchResult{Query: root.String()}}
+	if err := s.addPostings(ctx, root, postings, mode.search); err != nil {
+		return failed, err
+	}
+
+	ranked, err := mode.rank(ctx, root, postings)
+	if err != nil {
+		return failed, err
+	}
+	page, next, err := paginate(ranked, query, parsed.String())
+	if err != nil {
+		return failed, err
+	}
+
+	var suggestion string
+	var corrections map[string]string
+	if len(ranked) == 0 {
+		corrections, err = s.suggest(ctx, parsed, postings, mode.search)
+		if err != nil {
+			return failed, err
 		}
+	}
+	if len(corrections) > 0 {
+		suggestion = replaceSources(query.Keywords, sources, corrections)
+	}
 
-		cachedComics = append(cachedComics, comics)
-		count++
-		if count == query.Limit {
-			break
+	found := make(map[string]bool)
+	for _, word := range queryWords(root, true) {
+		found[word] = true
+	}
+	var explanations map[int]*Explanation
+	if query.Explain {
+		explanations = explain(root, postings, page, mode)
+	}
+	return rankedPage{
+		result: SearchResult{
+			Query:      root.String(),
+			Expansions: result,
+			Suggestion: suggestion,
+			Total:      len(ranked),
+			NextCursor: next,
+
+			corrections: corrections,
+		},
+		ranked:       page,
+		words:        found,
+		explanations: explanations,
+	}, nil
+}
+
+// addPostings fetches postings of query words that are not in the set yet.
+func (s *Service) addPostings(ctx context.Context, root queryNode, postings postingSet, search searchFunc) error {
+	var missing []string
+	for _, word := range root.words() {
+		if _, ok := postings[word]; !ok && !slices.Contains(missing, word) {
+			missing = append(missing, word)
 		}
 	}
+	more, err := s.collectPostings(ctx, missing, search)
+	if err != nil {
+		return err
+	}
+	maps.Copy(postings, more)
+	return nil
+}
 
-	return cachedComics, errGet
+// expand finds indexed words similar to query words nothing was found for.
+// The vocabulary comes from the in-memory index for both search modes.
+func (s *Service) expand(root queryNode, postings postingSet) (map[string][]Expansion, []Expansion) {
+	expansions := make(map[string][]Expansion)
+	var result []Expansion
+	for _, word := range queryWords(root, false) {
+		if _, done := expansions[word]; done || postings[word].docFreq() > 0 {
+			continue
+		}
+		similar := s.index.Load().Similar(word, fuzzyDistance(word))
+		if len(similar) == 0 {
+			continue
+		}
+		similar = similar[:min(len(similar), maxExpansions)]
+		expansions[word] = similar
+		result = append(result, similar...)
+	}
+	return expansions, result
+}
 
+// Search looks for comics in the database with the configured backend,
+// the full-text backend takes postings for fuzzy matching from the index.
+func (s *Service) Search(ctx context.Context, query SearchQuery) (SearchResult, error) {
+	parsed, err := s.parse(ctx, query.Keywords)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	return s.cached(s.backend, query, parsed, func() (SearchResult, error) {
+		mode, err := s.backendMode(ctx)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		return s.search(ctx, query, parsed, mode)
+	})
 }
 
-func (s *Service) BuildIndex(ctx context.Context) error {
+// backendMode is how Search finds and ranks comics with the configured
+// backend.
+func (s *Service) backendMode(ctx context.Context) (searchMode, error) {
+	if s.backend == BackendFullText {
+		index := s.index.Load()
+		return searchMode{
+			name:   "db:" + BackendFullText,
+			search: indexSearch(index),
+			rank:   s.rankFullText,
+		}, nil
+	}
 
-	s.index.Drop()
-	maxId, err := s.db.MaxId(ctx)
+	stats, err := s.db.Stats(ctx)
 	if err != nil {
-		return err
+		return searchMode{}, fmt.Errorf("failed to get corpus stats: %w", err)
+	}
+	stats.Boosts = s.boosts
+	return searchMode{name: "db:" + BackendArray, search: s.dbSearch, rank: rankBM25(stats), bm25: true, stats: stats}, nil
+}
+
+func (s *Service) SearchIndex(ctx context.Context, query SearchQuery) (SearchResult, error) {
+	parsed, err := s.parse(ctx, query.Keywords)
+	if err != nil {
+		return SearchResult{}, err
 	}
+	return s.cached("index", query, parsed, func() (SearchResult, error) {
+		index := s.index.Load()
+		stats := index.Stats()
+		stats.Boosts = s.boosts
+		mode := searchMode{name: "index", search: indexSearch(index), rank: rankBM25(stats), bm25: true, stats: stats}
+		return s.search(ctx, query, parsed, mode)
+	})
+}
 
-	for comicsId := 1; comicsId <= maxId; comicsId++ {
-		var item Comics
-		item, err = s.db.Get(ctx, comicsId)
+// BuildIndex brings the index up to date with the database. Only comics
+// added or changed since the last build are read into a copy of the
+// index, the index is built from scratch when comics were removed. Either
+// way the new index replaces the old one at once.
+func (s *Service) BuildIndex(ctx context.Context) (err error) {
+	s.statusMu.Lock()
+	if s.status.Status == StatusRunning {
+		s.statusMu.Unlock()
+		return ErrAlreadyExists
+	}
+	s.status.Status = StatusRunning
+	s.statusMu.Unlock()
+
+	start := time.Now()
+	defer func() {
+		s.statusMu.Lock()
+		defer s.statusMu.Unlock()
+		s.status.Status = StatusIdle
+		s.status.LastBuild = time.Now()
+		s.status.Duration = time.Since(start)
+		s.status.LastError = ""
 		if err != nil {
-			return err
+			s.status.LastError = err.Error()
+		}
+	}()
+
+	// searches keep using the old index until the updated one is complete,
+	// it is copied once the first changed comic arrives
+	index := s.index.Load()
+	updated := index
+	err = s.db.Updated(ctx, index.Version(), func(c Comics) error {
+		if updated == index {
+			updated = index.clone()
+		}
+		updated.Put(c)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get changed comics: %w", err)
+	}
+	stats, err := s.db.Stats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get corpus stats: %w", err)
+	}
+	if updated != index {
+		updated.Commit()
+	}
+	if updated.Stats().Docs == stats.Docs {
+		if updated != index {
+			s.index.Store(updated)
+			s.cache.Clear()
+			s.saveSnapshot(updated)
 		}
-		s.index.Add(comicsId, item.Words)
+		return nil
 	}
 
+	// comics were removed from the database, the index is built anew
+	// aside so that searches keep using the old one meanwhile
+	s.log.Info("rebuilding index", "indexed", updated.Stats().Docs, "stored", stats.Docs)
+	fresh := NewIndex()
+	err = s.db.Updated(ctx, 0, func(c Comics) error {
+		fresh.Put(c)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get comics: %w", err)
+	}
+	fresh.Commit()
+	s.index.Store(fresh)
+	s.cache.Clear()
+	s.saveSnapshot(fresh)
 	return nil
 }
+
+// LoadIndex restores the index from the last snapshot and brings it up
+// to date with BuildIndex, which reads only comics changed since the
+// snapshot was saved.
+func (s *Service) LoadIndex(ctx context.Context) error {
+	snapshot, err := s.snapshots.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load index snapshot: %w", err)
+	}
+	s.index.Store(indexFromSnapshot(snapshot))
+	s.log.Info("index snapshot loaded", "comics", len(snapshot.Comics), "version", snapshot.Version)
+
+	return s.BuildIndex(ctx)
+}
+
+// saveSnapshot keeps the built index for the next start, the index is
+// rebuilt from the database if it is lost, so failures are only logged.
+func (s *Service) saveSnapshot(index *Index) {
+	if err := s.snapshots.Save(index.Snapshot()); err != nil {
+		s.log.Error("failed to save index snapshot", "error", err)
+	}
+}
+
+func (s *Service) IndexStatus(_ context.Context) (IndexStatus, error) {
+	s.statusMu.RLock()
+	status := s.status
+	s.statusMu.RUnlock()
+
+	index := s.index.Load()
+	status.Comics = index.Stats().Docs
+	status.Vocabulary = index.VocabularySize()
+	status.Cache = s.cache.Stats()
+	return status, nil
+}
+
+func (s *Service) Suggest(_ context.Context, prefix string, limit int) ([]Completion, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
+		return nil, ErrBadArguments
+	}
+	return s.index.Load().Complete(prefix, limit), nil
+}
+
+// GetComic returns the comic with its texts from the database.
+func (s *Service) GetComic(ctx context.Context, id int) (Comics, error) {
+	if id <= 0 {
+		return Comics{}, fmt.Errorf("%w: comic id must be positive", ErrBadArguments)
+	}
+	comics, err := s.db.Get(ctx, id)
+	if err != nil {
+		return Comics{}, fmt.Errorf("failed to get comic %d: %w", id, err)
+	}
+	return comics, nil
+}
@@ -0,0 +1,438 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryDB keeps comics in memory and fails the test when comics
+// are searched in the database instead of the index unless searchable
+// is set.
+type memoryDB struct {
+	t          *testing.T
+	comics     []Comics
+	version    int64
+	searchable bool
+}
+
+func (db *memoryDB) put(id int, text string) {
+	tokens, _ := splitNorm(context.Background(), text)
+	db.version++
+	db.comics = slices.DeleteFunc(db.comics, func(c Comics) bool { return c.ID == id })
+	db.comics = append(db.comics, Comics{ID: id, URL: text, Tokens: tokens, Version: db.version})
+}
+
+func (db *memoryDB) CheckDB() error { return nil }
+
+func (db *memoryDB) Search(_ context.Context, word string) ([]Posting, error) {
+	if !db.searchable {
+		db.t.Error("indexed search queried the database")
+		return nil, nil
+	}
+	var postings []Posting
+	for _, c := range db.comics {
+		p := Posting{ID: c.ID, Len: len(c.Tokens)}
+		for _, t := range c.Tokens {
+			if t.Word == word {
+				p.Freq++
+				p.Positions = append(p.Positions, t.Position)
+			}
+		}
+		if p.Freq > 0 {
+			postings = append(postings, p)
+		}
+	}
+	return postings, nil
+}
+
+func (db *memoryDB) FullText(context.Context, TextQuery) ([]Comics, error) {
+	db.t.Error("indexed search used full-text search")
+	return nil, nil
+}
+
+func (db *memoryDB) Stats(context.Context) (CorpusStats, error) {
+	stats := CorpusStats{Docs: len(db.comics)}
+	for _, c := range db.comics {
+		stats.AvgLen += float64(len(c.Tokens)) / float64(len(db.comics))
+	}
+	return stats, nil
+}
+
+func (db *memoryDB) Get(_ context.Context, id int) (Comics, error) {
+	for _, c := range db.comics {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return Comics{}, ErrNotFound
+}
+
+func (db *memoryDB) GetMany(_ context.Context, ids []int) ([]Comics, error) {
+	var found []Comics
+	for _, c := range db.comics {
+		if slices.Contains(ids, c.ID) {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+func (db *memoryDB) Updated(_ context.Context, version int64, fn func(Comics) error) error {
+	for _, c := range db.comics {
+		if c.Version > version {
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (db *memoryDB) RandomID(context.Context) (int, error) {
+	if len(db.comics) == 0 {
+		return 0, ErrNotFound
+	}
+	return db.comics[rand.IntN(len(db.comics))].ID, nil
+}
+
+func (db *memoryDB) MaxId(context.Context) (int, error) {
+	var id int
+	for _, c := range db.comics {
+		id = max(id, c.ID)
+	}
+	return id, nil
+}
+
+type memorySnapshots struct {
+	saved *Snapshot
+}
+
+func (m *memorySnapshots) Save(snapshot Snapshot) error {
+	m.saved = &snapshot
+	return nil
+}
+
+func (m *memorySnapshots) Load() (Snapshot, error) {
+	if m.saved == nil {
+		return Snapshot{}, ErrNotFound
+	}
+	return *m.saved, nil
+}
+
+type memorySynonyms struct {
+	groups []SynonymGroup
+}
+
+func (m *memorySynonyms) Save(groups []SynonymGroup) error {
+	m.groups = groups
+	return nil
+}
+
+func (m *memorySynonyms) Load() ([]SynonymGroup, error) {
+	if m.groups == nil {
+		return nil, ErrNotFound
+	}
+	return m.groups, nil
+}
+
+// testWords serves splitNorm as the words service.
+type testWords struct{}
+
+func (testWords) Norm(ctx context.Context, phrase string) ([]Token, error) {
+	return splitNorm(ctx, phrase)
+}
+
+// testOptions changes the service made by newTestService, zero fields
+// keep defaults: an empty memoryDB and stores, testWords, the array
+// backend and no cache.
+type testOptions struct {
+	db        DB
+	words     Words
+	snapshots Snapshots
+	synonyms  SynonymStore
+	backend   string
+	boosts    FieldBoosts
+	cacheSize int
+}
+
+func newTestService(t *testing.T, opts testOptions) *Service {
+	t.Helper()
+	if opts.db == nil {
+		opts.db = &memoryDB{t: t}
+	}
+	if opts.snapshots == nil {
+		opts.snapshots = &memorySnapshots{}
+	}
+	if opts.synonyms == nil {
+		opts.synonyms = &memorySynonyms{}
+	}
+	if opts.words == nil {
+		opts.words = testWords{}
+	}
+	if opts.backend == "" {
+		opts.backend = BackendArray
+	}
+	service, err := NewService(
+		slog.Default(), opts.db, opts.words, opts.snapshots, opts.synonyms,
+		opts.backend, opts.boosts, NewQueryCache(opts.cacheSize, 0),
+	)
+	require.NoError(t, err)
+	return service
+}
+
+func searchIndexIDs(t *testing.T, service *Service, keywords string) []int {
+	result, err := service.SearchIndex(context.Background(), SearchQuery{Keywords: keywords, Limit: 10})
+	require.NoError(t, err)
+
+	ids := make([]int, 0)
+	for _, c := range result.Comics {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestSearchIndexUsesIndex(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+	db.put(2, "dog chasing a cat")
+	db.put(3, "python")
+
+	service := newTestService(t, testOptions{db: db})
+	require.NoError(t, service.BuildIndex(context.Background()))
+
+	assert.ElementsMatch(t, []int{1, 2}, searchIndexIDs(t, service, "cat"))
+}
+
+func TestBuildIndexIncremental(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+	db.put(2, "dog chasing a cat")
+
+	service := newTestService(t, testOptions{db: db})
+	require.NoError(t, service.BuildIndex(context.Background()))
+	first := service.index.Load()
+
+	// new and changed comics are added to a copy swapped in at once,
+	// searches still using the old index see none of them
+	db.put(3, "cat again")
+	db.put(2, "dog chasing a ball")
+	require.NoError(t, service.BuildIndex(context.Background()))
+	updated := service.index.Load()
+	assert.NotSame(t, first, updated)
+	assert.ElementsMatch(t, []int{1, 3}, searchIndexIDs(t, service, "cat"))
+	assert.ElementsMatch(t, []int{2}, searchIndexIDs(t, service, "ball"))
+	assert.Equal(t, int64(4), updated.Version())
+	assert.Equal(t, int64(2), first.Version())
+	assert.Equal(t, 2, first.Stats().Docs)
+	assert.Equal(t, 2, first.lookup("cat").docFreq())
+	assert.Zero(t, first.lookup("ball").docFreq())
+	assert.Empty(t, first.Similar("bal", 1))
+
+	// nothing changed, nothing swapped
+	require.NoError(t, service.BuildIndex(context.Background()))
+	assert.Same(t, updated, service.index.Load())
+
+	// removed comics make the index built anew
+	db.comics = db.comics[:1]
+	require.NoError(t, service.BuildIndex(context.Background()))
+	assert.NotSame(t, updated, service.index.Load())
+	assert.ElementsMatch(t, []int{1}, searchIndexIDs(t, service, "cat"))
+	assert.Equal(t, 1, service.index.Load().Stats().Docs)
+}
+
+// watchedDB calls watch after every comic it streams.
+type watchedDB struct {
+	*memoryDB
+	watch func()
+}
+
+func (db *watchedDB) Updated(ctx context.Context, version int64, fn func(Comics) error) error {
+	return db.memoryDB.Updated(ctx, version, func(c Comics) error {
+		if err := fn(c); err != nil {
+			return err
+		}
+		db.watch()
+		return nil
+	})
+}
+
+func TestBuildIndexStreams(t *testing.T) {
+	db := &watchedDB{memoryDB: &memoryDB{t: t}}
+	for id := 1; id <= 5; id++ {
+		db.put(id, "cat")
+	}
+	service := newTestService(t, testOptions{db: db})
+	empty := service.index.Load()
+
+	// comics go into the new index one by one as they arrive,
+	// searches see none of them until all are there
+	streamed := 0
+	db.watch = func() {
+		streamed++
+		assert.Same(t, empty, service.index.Load())
+		assert.Empty(t, searchIndexIDs(t, service, "cat"))
+	}
+	require.NoError(t, service.BuildIndex(context.Background()))
+	assert.Equal(t, 5, streamed)
+	assert.Len(t, searchIndexIDs(t, service, "cat"), 5)
+}
+
+func TestLoadIndex(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+	db.put(2, "dog chasing a cat")
+	snapshots := &memorySnapshots{}
+
+	service := newTestService(t, testOptions{db: db, snapshots: snapshots})
+	assert.ErrorIs(t, service.LoadIndex(context.Background()), ErrNotFound)
+	require.NoError(t, service.BuildIndex(context.Background()))
+
+	restarted := newTestService(t, testOptions{db: db, snapshots: snapshots})
+	require.NoError(t, restarted.LoadIndex(context.Background()))
+	assert.Equal(t, int64(2), restarted.index.Load().Version())
+	assert.Equal(t, service.index.Load().Snapshot(), restarted.index.Load().Snapshot())
+	assert.ElementsMatch(t, []int{1, 2}, searchIndexIDs(t, restarted, "cat"))
+
+	// a changed comic leaves the largest ID as it was
+	db.put(2, "dog chasing a ball")
+	restarted = newTestService(t, testOptions{db: db, snapshots: snapshots})
+	require.NoError(t, restarted.LoadIndex(context.Background()))
+	assert.Equal(t, int64(3), restarted.index.Load().Version())
+	assert.ElementsMatch(t, []int{1}, searchIndexIDs(t, restarted, "cat"))
+	assert.ElementsMatch(t, []int{2}, searchIndexIDs(t, restarted, "ball"))
+
+	// so does a removed one
+	db.comics = slices.DeleteFunc(db.comics, func(c Comics) bool { return c.ID == 1 })
+	restarted = newTestService(t, testOptions{db: db, snapshots: snapshots})
+	require.NoError(t, restarted.LoadIndex(context.Background()))
+	assert.Empty(t, searchIndexIDs(t, restarted, "cat"))
+	assert.Equal(t, 1, restarted.index.Load().Stats().Docs)
+}
+
+func TestSearchIndexSkipsRemovedComics(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+	db.put(5, "dog chasing a cat")
+	db.put(9, "cat again")
+
+	service := newTestService(t, testOptions{db: db})
+	require.NoError(t, service.BuildIndex(context.Background()))
+
+	// removed after the index was built
+	db.comics = slices.DeleteFunc(db.comics, func(c Comics) bool { return c.ID == 5 })
+	assert.ElementsMatch(t, []int{1, 9}, searchIndexIDs(t, service, "cat"))
+}
+
+func TestSearchIndexPages(t *testing.T) {
+	db := &memoryDB{t: t}
+	for id := 1; id <= 5; id++ {
+		db.put(id, "cat")
+	}
+	service := newTestService(t, testOptions{db: db})
+	require.NoError(t, service.BuildIndex(context.Background()))
+	ctx := context.Background()
+
+	var ids []int
+	query := SearchQuery{Keywords: "cat", Limit: 2}
+	for {
+		result, err := service.SearchIndex(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Total)
+		for _, c := range result.Comics {
+			ids = append(ids, c.ID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		query.Cursor = result.NextCursor
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+
+	result, err := service.SearchIndex(ctx, SearchQuery{Keywords: "cat", Limit: 2, Offset: 4})
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Comics[0].ID)
+	assert.Empty(t, result.NextCursor)
+
+	_, err = service.SearchIndex(ctx, SearchQuery{Keywords: "dog", Limit: 2, Cursor: query.Cursor})
+	assert.ErrorIs(t, err, ErrBadArguments)
+	_, err = service.SearchIndex(ctx, SearchQuery{Keywords: "cat", Offset: 1, Cursor: query.Cursor})
+	assert.ErrorIs(t, err, ErrBadArguments)
+}
+
+func TestSearchIndexSuggestion(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "black dog on a keyboard")
+	db.put(2, "cat chasing a dog")
+	db.put(3, "python tables")
+
+	service := newTestService(t, testOptions{db: db})
+	require.NoError(t, service.BuildIndex(context.Background()))
+
+	tests := []struct {
+		keywords string
+		want     string
+	}{
+		// too short to be expanded, excluded words are not corrected
+		{keywords: "Cst -pythn", want: "cat -pythn"},
+		// phrases are not expanded, keybord is but the phrase fails
+		{keywords: `"BLACK  doq" AND keybord`, want: `"BLACK  dog" AND keyboard`},
+		{keywords: "dog", want: ""},
+		{keywords: "zzzzzz", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keywords, func(t *testing.T) {
+			result, err := service.SearchIndex(context.Background(), SearchQuery{Keywords: tt.keywords, Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Suggestion)
+			if tt.want != "" {
+				assert.Zero(t, result.Total)
+			}
+		})
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "little bobby tables drops students table")
+	db.put(2, "bobby tables again")
+	db.put(3, "students drop out of school")
+	db.put(4, "a cat on a keyboard")
+	db.put(5, "a cat on a desk")
+
+	service := newTestService(t, testOptions{db: db})
+	require.NoError(t, service.BuildIndex(context.Background()))
+
+	comics, err := service.Similar(context.Background(), 1, 10)
+	require.NoError(t, err)
+	ids := make([]int, 0, len(comics))
+	for _, c := range comics {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []int{2, 3}, ids)
+
+	_, err = service.Similar(context.Background(), 42, 10)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = service.Similar(context.Background(), 1, 0)
+	assert.ErrorIs(t, err, ErrBadArguments)
+}
+
+func TestGetComic(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "cat on a keyboard")
+	service := newTestService(t, testOptions{db: db})
+
+	comics, err := service.GetComic(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, comics.ID)
+
+	_, err = service.GetComic(context.Background(), 2)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = service.GetComic(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrBadArguments)
+}
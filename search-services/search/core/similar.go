@@ -0,0 +1,72 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// similarTerms is how many of the best words of a comic make up the query
+// for comics like it.
+const similarTerms = 25
+
+// weightedTerm is a word with its weight: TF-IDF of a comic word
+// or how much a query word counts.
+type weightedTerm struct {
+	word   string
+	weight float64
+}
+
+// Similar finds comics sharing the most distinctive words with the given
+// one: its words weighted by TF-IDF make up a query, the comic itself
+// is left out of the result.
+func (s *Service) Similar(ctx context.Context, id, limit int) ([]Comics, error) {
+	if id <= 0 || limit <= 0 {
+		return nil, ErrBadArguments
+	}
+	index := s.index.Load()
+	stats := index.Stats()
+	stats.Boosts = s.boosts
+
+	own := index.comicPostings(id)
+	if len(own) == 0 {
+		return nil, ErrNotFound
+	}
+
+	postings := make(postingSet, len(own))
+	terms := make([]weightedTerm, 0, len(own))
+	for word, p := range own {
+		list := index.lookup(word)
+		// words of this comic only can not find others
+		if list.docFreq() < 2 {
+			continue
+		}
+		postings[word] = list
+		terms = append(terms, weightedTerm{
+			word:   word,
+			weight: termFreq(p, stats.Boosts) * idf(stats.Docs, list.docFreq()),
+		})
+	}
+	slices.SortFunc(terms, func(a, b weightedTerm) int {
+		if c := cmp.Compare(b.weight, a.weight); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.word, b.word)
+	})
+	terms = terms[:min(len(terms), similarTerms)]
+
+	found := make(matches)
+	words := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		hits := termNode{word: t.word}.eval(postings, stats, nil)
+		for _, h := range hits {
+			h.score *= t.weight
+		}
+		union(found, hits)
+		words[t.word] = true
+	}
+	delete(found, id)
+
+	ranked := prioritySorting(found)
+	return s.fetchComics(ctx, ranked[:min(len(ranked), limit)], words)
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetWords is the length of a snippet, snippetContext is how many
+	// words it shows before the first found one.
+	snippetWords   = 20
+	snippetContext = 5
+)
+
+// fieldText is the original text of a comic field and the position
+// of its first word.
+type fieldText struct {
+	field Field
+	text  string
+	start int
+}
+
+// fieldTexts returns texts of comic fields in the order the update service
+// numbers them, nil for comics stored without field starts.
+func (c Comics) fieldTexts() []fieldText {
+	texts := []fieldText{
+		{field: FieldTitle, text: c.Title},
+		{field: FieldAlt, text: c.Alt},
+		{field: FieldTranscript, text: c.Transcript},
+	}
+	if len(c.FieldStarts) != len(texts) {
+		return nil
+	}
+	for i := range texts {
+		texts[i].start = c.FieldStarts[i]
+	}
+	return texts
+}
+
+// textRange is a word of a text as byte offsets, the end excluded.
+type textRange struct {
+	start, end int
+}
+
+// textWords splits a text into runs of letters and digits, the words
+// service numbers positions of words the same way.
+func textWords(text string) []textRange {
+	var words []textRange
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			words = append(words, textRange{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, textRange{start: start, end: len(text)})
+	}
+	return words
+}
+
+// surfaceForms returns the lower-cased word the comic text uses most for
+// every token word written differently, comics without texts return
+// the forms they carry.
+func (c Comics) surfaceForms() map[string]string {
+	texts := c.fieldTexts()
+	if texts == nil || c.Title == "" && c.Alt == "" && c.Transcript == "" {
+		return c.Forms
+	}
+	words := make([][]textRange, len(texts))
+	for k, ft := range texts {
+		words[k] = textWords(ft.text)
+	}
+
+	counts := make(map[string]map[string]int)
+	for _, t := range c.Tokens {
+		k := tokenField(texts, t)
+		n := t.Position - texts[k].start
+		if n < 0 || n >= len(words[k]) {
+			continue
+		}
+		w := words[k][n]
+		if counts[t.Word] == nil {
+			counts[t.Word] = make(map[string]int)
+		}
+		counts[t.Word][strings.ToLower(texts[k].text[w.start:w.end])]++
+	}
+
+	forms := make(map[string]string)
+	for word, count := range counts {
+		best, bestCount := "", 0
+		for form, n := range count {
+			if n > bestCount || n == bestCount && form < best {
+				best, bestCount = form, n
+			}
+		}
+		if best != word {
+			// the form must not keep the whole text in memory
+			forms[word] = strings.Clone(best)
+		}
+	}
+	return forms
+}
+
+// tokenField returns the number of the text a token comes from, tokens
+// stored without fields are placed by their positions.
+func tokenField(texts []fieldText, t Token) int {
+	for k := len(texts) - 1; k > 0; k-- {
+		if t.Field != "" && texts[k].field == t.Field || t.Field == "" && texts[k].start <= t.Position {
+			return k
+		}
+	}
+	return 0
+}
+
+// snippet returns a piece of the alt text or the transcript around the
+// first found word, found words are marked with <b>. Comics found by the
+// title only get the beginning of the alt text. The snippet is HTML.
+func snippet(c Comics, words map[string]bool) string {
+	for _, ft := range c.fieldTexts() {
+		if ft.field == FieldTitle {
+			continue
+		}
+		found := make(map[int]bool)
+		for _, t := range c.Tokens {
+			if t.Field == ft.field && words[t.Word] {
+				found[t.Position-ft.start] = true
+			}
+		}
+		if len(found) > 0 && ft.text != "" {
+			return highlight(ft.text, found)
+		}
+	}
+	return highlight(c.Alt, nil)
+}
+
+// highlight cuts snippetWords words of the text, found are word numbers.
+func highlight(text string, found map[int]bool) string {
+	words := textWords(text)
+	if len(words) == 0 {
+		return ""
+	}
+	first := 0
+	for i := range words {
+		if found[i] {
+			first = i
+			break
+		}
+	}
+	from := max(0, first-snippetContext)
+	to := min(len(words), from+snippetWords)
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("… ")
+	}
+	for i := from; i < to; i++ {
+		if i > from {
+			b.WriteString(html.EscapeString(squashSpaces(text[words[i-1].end:words[i].start])))
+		}
+		word := html.EscapeString(text[words[i].start:words[i].end])
+		if found[i] {
+			word = "<b>" + word + "</b>"
+		}
+		b.WriteString(word)
+	}
+	if to < len(words) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
+
+// squashSpaces replaces runs of white space with a single space.
+func squashSpaces(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
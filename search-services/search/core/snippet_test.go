@@ -0,0 +1,85 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withTokens numbers words of comic fields as the update service does.
+func withTokens(c Comics) Comics {
+	c.FieldStarts = []int{0, len(c.Title) + 100, len(c.Title) + len(c.Alt) + 200}
+	for _, ft := range c.fieldTexts() {
+		for i, w := range textWords(ft.text) {
+			word := strings.ToLower(ft.text[w.start:w.end])
+			c.Tokens = append(c.Tokens, Token{Word: word, Position: ft.start + i, Field: ft.field})
+		}
+	}
+	return c
+}
+
+func TestSnippet(t *testing.T) {
+	comic := withTokens(Comics{
+		Title:      "Exploits of a Mom",
+		Alt:        "Her daughter is named Help I'm trapped in a driver's license factory.",
+		Transcript: "[[A woman is talking on the phone.]]\nPhone:   Hi, this is your son's school. We're having some computer trouble. Oh, dear.",
+	})
+
+	tests := []struct {
+		name  string
+		words []string
+		want  string
+	}{
+		{
+			name:  "alt",
+			words: []string{"daughter", "license"},
+			want:  "Her <b>daughter</b> is named Help I&#39;m trapped in a driver&#39;s <b>license</b> factory",
+		},
+		{
+			name:  "transcript",
+			words: []string{"phone"},
+			want:  "… woman is talking on the <b>phone</b>.]] <b>Phone</b>: Hi, this is your son&#39;s school. We&#39;re having some computer trouble …",
+		},
+		{
+			name:  "title only",
+			words: []string{"mom"},
+			want:  "Her daughter is named Help I&#39;m trapped in a driver&#39;s license factory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words := make(map[string]bool)
+			for _, w := range tt.words {
+				words[w] = true
+			}
+			assert.Equal(t, tt.want, snippet(comic, words))
+		})
+	}
+
+	assert.Empty(t, snippet(Comics{Tokens: []Token{{Word: "phone"}}}, map[string]bool{"phone": true}))
+
+	// without field starts words can not be found in the texts
+	unplaced := comic
+	unplaced.FieldStarts = nil
+	assert.Equal(t, tests[2].want, snippet(unplaced, map[string]bool{"daughter": true}))
+}
+
+func TestSurfaceForms(t *testing.T) {
+	// stems drop the plural s
+	comic := withTokens(Comics{Title: "Cats and Dogs", Alt: "The cat chased cats. Cats!"})
+	for i, token := range comic.Tokens {
+		comic.Tokens[i].Word = strings.TrimSuffix(token.Word, "s")
+	}
+	want := map[string]string{"cat": "cats", "dog": "dogs"}
+	assert.Equal(t, want, comic.surfaceForms())
+
+	for i := range comic.Tokens {
+		comic.Tokens[i].Field = ""
+	}
+	assert.Equal(t, want, comic.surfaceForms(), "tokens without fields")
+
+	stored := Comics{Tokens: comic.Tokens, Forms: want}
+	assert.Equal(t, want, stored.surfaceForms(), "comic without texts")
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"slices"
+)
+
+// streamBatch is how many comics are read from the database and sent
+// at once by SearchStream.
+const streamBatch = 100
+
+// SearchStream searches like Search, but found comics are passed to send
+// in batches in rank order instead of being returned at once. The result
+// without comics is sent first. Streams are not cached, the search stops
+// when ctx is done or send fails.
+func (s *Service) SearchStream(ctx context.Context, query SearchQuery, send func(SearchResult) error) error {
+	mode, err := s.backendMode(ctx)
+	if err != nil {
+		return err
+	}
+	parsed, err := s.parse(ctx, query.Keywords)
+	if err != nil {
+		return err
+	}
+	page, err := s.rankPage(ctx, query, parsed, mode)
+	if err != nil {
+		return err
+	}
+	if err := send(page.result); err != nil {
+		return err
+	}
+
+	for batch := range slices.Chunk(page.ranked, streamBatch) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		comics, err := s.fetchComics(ctx, batch, page.words)
+		if err != nil {
+			return err
+		}
+		if err := send(SearchResult{Comics: page.withExplanations(comics)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
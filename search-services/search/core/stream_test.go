@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchStream(t *testing.T) {
+	db := &memoryDB{t: t, searchable: true}
+	for id := 1; id <= 250; id++ {
+		db.put(id, "cat")
+	}
+	db.put(251, "dog")
+	service := newTestService(t, testOptions{db: db})
+
+	var results []SearchResult
+	err := service.SearchStream(context.Background(), SearchQuery{Keywords: "cat", Limit: 240}, func(r SearchResult) error {
+		results = append(results, r)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, 250, results[0].Total)
+	assert.Equal(t, "cat", results[0].Query)
+	assert.NotEmpty(t, results[0].NextCursor)
+	assert.Empty(t, results[0].Comics)
+
+	var ids []int
+	for _, r := range results[1:] {
+		for _, c := range r.Comics {
+			ids = append(ids, c.ID)
+		}
+	}
+	require.Len(t, ids, 240)
+	assert.Equal(t, 1, ids[0])
+	assert.Equal(t, 240, ids[239])
+
+	// the search stops when the client is gone
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := 0
+	err = service.SearchStream(ctx, SearchQuery{Keywords: "cat"}, func(r SearchResult) error {
+		sent++
+		if len(r.Comics) > 0 {
+			cancel()
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, sent)
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+)
+
+// suggest corrects unknown words of a query that found nothing and returns
+// the corrections as they are written in comics by query word if the
+// corrected query finds at least one comic.
+func (s *Service) suggest(ctx context.Context, root queryNode, postings postingSet, search searchFunc) (map[string]string, error) {
+	index := s.index.Load()
+	corrections := make(map[string]string)
+	for _, word := range queryWords(root, true) {
+		if postings[word].docFreq() > 0 {
+			continue
+		}
+		if correction, ok := index.Suggest(word); ok {
+			corrections[word] = correction
+		}
+	}
+	if len(corrections) == 0 {
+		return nil, nil
+	}
+
+	corrected := replaceWords(root, corrections)
+	if err := s.addPostings(ctx, corrected, postings, search); err != nil {
+		return nil, err
+	}
+	if corrected.docs(postings).IsEmpty() {
+		return nil, nil
+	}
+
+	written := make(map[string]string, len(corrections))
+	for word, correction := range corrections {
+		written[word] = index.Form(correction)
+	}
+	return written, nil
+}
+
+// replaceSources replaces the words of a query written where the sources
+// say with the replacements of their normalized words.
+func replaceSources(query string, sources []sourceWord, replacements map[string]string) string {
+	sources = slices.SortedFunc(slices.Values(sources), func(a, b sourceWord) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	var b strings.Builder
+	last := 0
+	for _, source := range sources {
+		replacement, ok := replacements[source.word]
+		if !ok || source.start < last {
+			continue
+		}
+		b.WriteString(query[last:source.start])
+		b.WriteString(replacement)
+		last = source.end
+	}
+	b.WriteString(query[last:])
+	return b.String()
+}
+
+// replaceWords returns a copy of the query with words replaced,
+// excluded parts are kept as they are.
+func replaceWords(node queryNode, replacements map[string]string) queryNode {
+	switch n := node.(type) {
+	case termNode:
+		if word, ok := replacements[n.word]; ok {
+			return termNode{word: word}
+		}
+	case phraseNode:
+		tokens := make([]Token, 0, len(n.tokens))
+		for _, token := range n.tokens {
+			if word, ok := replacements[token.Word]; ok {
+				token.Word = word
+			}
+			tokens = append(tokens, token)
+		}
+		return phraseNode{tokens: tokens}
+	case *fieldNode:
+		return &fieldNode{field: n.field, child: replaceWords(n.child, replacements)}
+	case *nearNode:
+		return &nearNode{
+			left:     replaceWords(n.left, replacements),
+			right:    replaceWords(n.right, replacements),
+			distance: n.distance,
+		}
+	case *orNode:
+		children := make([]queryNode, 0, len(n.children))
+		for _, child := range n.children {
+			children = append(children, replaceWords(child, replacements))
+		}
+		return &orNode{children: children}
+	case *boolNode:
+		replaced := &boolNode{mustNot: n.mustNot}
+		for _, child := range n.must {
+			replaced.must = append(replaced.must, replaceWords(child, replacements))
+		}
+		for _, child := range n.should {
+			replaced.should = append(replaced.should, replaceWords(child, replacements))
+		}
+		return replaced
+	}
+	return node
+}
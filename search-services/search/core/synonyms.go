@@ -0,0 +1,228 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/RoaringBitmap/roaring/v2"
+)
+
+// synonymWeight lowers scores of synonyms so the words of the query
+// itself rank higher.
+const synonymWeight = 0.5
+
+// synonymDict maps a normalized word to the normalized words of its groups.
+type synonymDict map[string][]string
+
+// newSynonymDict normalizes words of the groups, every word has to stay
+// a single word. Words that normalize to nothing, such as stop words,
+// are ignored, words with the same normalized form count once.
+func newSynonymDict(ctx context.Context, groups []SynonymGroup, words Words) (synonymDict, error) {
+	dict := make(synonymDict)
+	for _, group := range groups {
+		normalized := make([]string, 0, len(group.Words))
+		for _, word := range group.Words {
+			stem, ok, err := normSynonym(ctx, words, word)
+			if err != nil {
+				return nil, err
+			}
+			if ok && !slices.Contains(normalized, stem) {
+				normalized = append(normalized, stem)
+			}
+		}
+		for _, word := range normalized {
+			for _, other := range normalized {
+				if other != word && !slices.Contains(dict[word], other) {
+					dict[word] = append(dict[word], other)
+				}
+			}
+		}
+	}
+	return dict, nil
+}
+
+// normSynonym returns the normalized word, false if nothing is left
+// of it after normalization.
+func normSynonym(ctx context.Context, words Words, word string) (string, bool, error) {
+	tokens, err := words.Norm(ctx, word)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to normalize synonym %q: %w", word, err)
+	}
+	switch len(tokens) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return tokens[0].Word, true, nil
+	}
+	return "", false, fmt.Errorf("%w: synonym %q is not a single word", ErrBadArguments, word)
+}
+
+// synonymNode is a query word with its synonyms.
+type synonymNode struct {
+	word     string
+	synonyms []string
+}
+
+func (n synonymNode) words() []string {
+	return append([]string{n.word}, n.synonyms...)
+}
+
+func (n synonymNode) docs(postings postingSet) *roaring.Bitmap {
+	result := postings.ids(n.word).Clone()
+	for _, synonym := range n.synonyms {
+		result.Or(postings.ids(synonym))
+	}
+	return result
+}
+
+func (n synonymNode) eval(postings postingSet, stats CorpusStats, within *roaring.Bitmap) matches {
+	result := termNode{word: n.word}.eval(postings, stats, within)
+	for _, synonym := range n.synonyms {
+		found := termNode{word: synonym}.eval(postings, stats, within)
+		for _, h := range found {
+			h.score *= synonymWeight
+		}
+		union(result, found)
+	}
+	return result
+}
+
+func (n synonymNode) String() string {
+	return "(" + strings.Join(n.words(), " OR ") + ")"
+}
+
+// withSynonyms adds synonyms to words of the query.
+// Phrases and excluded parts are left exact.
+func withSynonyms(node queryNode, dict synonymDict) queryNode {
+	switch n := node.(type) {
+	case termNode:
+		if synonyms := dict[n.word]; len(synonyms) > 0 {
+			return synonymNode{word: n.word, synonyms: synonyms}
+		}
+	case *fieldNode:
+		return &fieldNode{field: n.field, child: withSynonyms(n.child, dict)}
+	case *nearNode:
+		return &nearNode{
+			left:     withSynonyms(n.left, dict),
+			right:    withSynonyms(n.right, dict),
+			distance: n.distance,
+		}
+	case *orNode:
+		children := make([]queryNode, 0, len(n.children))
+		for _, child := range n.children {
+			children = append(children, withSynonyms(child, dict))
+		}
+		return &orNode{children: children}
+	case *boolNode:
+		expanded := &boolNode{mustNot: n.mustNot}
+		for _, child := range n.must {
+			expanded.must = append(expanded.must, withSynonyms(child, dict))
+		}
+		for _, child := range n.should {
+			expanded.should = append(expanded.should, withSynonyms(child, dict))
+		}
+		return expanded
+	}
+	return node
+}
+
+// LoadSynonyms reads synonym groups from the store, the groups in use
+// are kept if the new ones can not be used.
+func (s *Service) LoadSynonyms(ctx context.Context) error {
+	s.synonymsMu.Lock()
+	defer s.synonymsMu.Unlock()
+
+	groups, err := s.synonymStore.Load()
+	if errors.Is(err, ErrNotFound) {
+		groups = nil
+	} else if err != nil {
+		return fmt.Errorf("failed to load synonyms: %w", err)
+	}
+	dict, err := newSynonymDict(ctx, groups, s.words)
+	if err != nil {
+		return err
+	}
+	s.synonymGroups = groups
+	s.synonyms.Store(&dict)
+	s.cache.Clear()
+	return nil
+}
+
+func (s *Service) Synonyms(_ context.Context) ([]SynonymGroup, error) {
+	s.synonymsMu.Lock()
+	defer s.synonymsMu.Unlock()
+	return slices.Clone(s.synonymGroups), nil
+}
+
+// AddSynonyms adds a new group, a word may be in one group only. Words
+// with the same normalized form are kept once, words that normalize to
+// nothing are dropped.
+func (s *Service) AddSynonyms(ctx context.Context, group SynonymGroup) error {
+	words := make([]string, 0, len(group.Words))
+	stems := make([]string, 0, len(group.Words))
+	for _, word := range group.Words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		stem, ok, err := normSynonym(ctx, s.words, word)
+		if err != nil {
+			return err
+		}
+		if ok && !slices.Contains(stems, stem) {
+			words = append(words, word)
+			stems = append(stems, stem)
+		}
+	}
+	if len(words) < 2 {
+		return fmt.Errorf("%w: a group needs at least two different words", ErrBadArguments)
+	}
+
+	s.synonymsMu.Lock()
+	defer s.synonymsMu.Unlock()
+	dict := *s.synonyms.Load()
+	for i, word := range words {
+		if s.synonymGroup(word) >= 0 || len(dict[stems[i]]) > 0 {
+			return fmt.Errorf("%w: %q is in a group already", ErrAlreadyExists, word)
+		}
+	}
+	return s.saveSynonyms(ctx, append(slices.Clone(s.synonymGroups), SynonymGroup{Words: words}))
+}
+
+// RemoveSynonyms removes the group with the word.
+func (s *Service) RemoveSynonyms(ctx context.Context, word string) error {
+	s.synonymsMu.Lock()
+	defer s.synonymsMu.Unlock()
+
+	i := s.synonymGroup(strings.ToLower(strings.TrimSpace(word)))
+	if i < 0 {
+		return ErrNotFound
+	}
+	return s.saveSynonyms(ctx, slices.Delete(slices.Clone(s.synonymGroups), i, i+1))
+}
+
+// synonymGroup returns the number of the group with the word or -1.
+func (s *Service) synonymGroup(word string) int {
+	return slices.IndexFunc(s.synonymGroups, func(g SynonymGroup) bool {
+		return slices.Contains(g.Words, word)
+	})
+}
+
+// saveSynonyms stores and starts using the groups if every word of them
+// can be used.
+func (s *Service) saveSynonyms(ctx context.Context, groups []SynonymGroup) error {
+	dict, err := newSynonymDict(ctx, groups, s.words)
+	if err != nil {
+		return err
+	}
+	if err := s.synonymStore.Save(groups); err != nil {
+		return fmt.Errorf("failed to save synonyms: %w", err)
+	}
+	s.synonymGroups = groups
+	s.synonyms.Store(&dict)
+	s.cache.Clear()
+	return nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynonyms(t *testing.T) {
+	db := &memoryDB{t: t}
+	db.put(1, "automobile factory")
+	db.put(2, "car crash")
+	db.put(3, "cat")
+	store := &memorySynonyms{groups: []SynonymGroup{{Words: []string{"linux", "gnu"}}}}
+
+	service := newTestService(t, testOptions{db: db, synonyms: store, cacheSize: 10})
+	require.NoError(t, service.BuildIndex(context.Background()))
+	ctx := context.Background()
+
+	require.NoError(t, service.LoadSynonyms(ctx))
+	groups, err := service.Synonyms(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, store.groups, groups)
+	assert.Equal(t, []int{2}, searchIndexIDs(t, service, "car"))
+
+	// the word itself ranks above its synonyms
+	require.NoError(t, service.AddSynonyms(ctx, SynonymGroup{Words: []string{" Car", "automobile", "car"}}))
+	assert.Equal(t, []int{2, 1}, searchIndexIDs(t, service, "car"))
+	assert.Equal(t, []int{1, 2}, searchIndexIDs(t, service, "automobile"))
+	assert.Equal(t, []int{2}, searchIndexIDs(t, service, `"car crash"`))
+	result, err := service.SearchIndex(ctx, SearchQuery{Keywords: "car", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "(car OR automobile)", result.Query)
+	assert.Len(t, store.groups, 2)
+
+	err = service.AddSynonyms(ctx, SynonymGroup{Words: []string{"auto", "car"}})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	err = service.AddSynonyms(ctx, SynonymGroup{Words: []string{"auto", "auto"}})
+	assert.ErrorIs(t, err, ErrBadArguments)
+	err = service.AddSynonyms(ctx, SynonymGroup{Words: []string{"auto", "motor car"}})
+	assert.ErrorIs(t, err, ErrBadArguments)
+
+	require.NoError(t, service.RemoveSynonyms(ctx, "automobile"))
+	assert.Equal(t, []int{2}, searchIndexIDs(t, service, "car"))
+	assert.ErrorIs(t, service.RemoveSynonyms(ctx, "automobile"), ErrNotFound)
+	assert.Equal(t, []SynonymGroup{{Words: []string{"linux", "gnu"}}}, store.groups)
+}
+
+// pluralWords normalizes like testWords and drops the plural "s".
+type pluralWords struct{}
+
+func (pluralWords) Norm(ctx context.Context, phrase string) ([]Token, error) {
+	tokens, err := splitNorm(ctx, phrase)
+	for i := range tokens {
+		tokens[i].Word = strings.TrimSuffix(tokens[i].Word, "s")
+	}
+	return tokens, err
+}
+
+func TestSynonymsNormalized(t *testing.T) {
+	ctx := context.Background()
+	dict, err := newSynonymDict(ctx, []SynonymGroup{
+		{Words: []string{"the", "car", "cars", "automobile"}},
+		{Words: []string{"the", "dogs", "dog"}},
+	}, pluralWords{})
+	require.NoError(t, err)
+	assert.Equal(t, synonymDict{"car": {"automobile"}, "automobile": {"car"}}, dict)
+
+	db := &memoryDB{t: t}
+	db.put(1, "automobile")
+	store := &memorySynonyms{}
+	service := newTestService(t, testOptions{db: db, words: pluralWords{}, synonyms: store})
+	require.NoError(t, service.BuildIndex(ctx))
+
+	// stop words are dropped, words with the same stem are kept once
+	require.NoError(t, service.AddSynonyms(ctx, SynonymGroup{Words: []string{"The", "Cars", "car", "automobile"}}))
+	assert.Equal(t, []SynonymGroup{{Words: []string{"cars", "automobile"}}}, store.groups)
+	assert.Equal(t, []int{1}, searchIndexIDs(t, service, "car"))
+
+	err = service.AddSynonyms(ctx, SynonymGroup{Words: []string{"autos", "car"}})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	err = service.AddSynonyms(ctx, SynonymGroup{Words: []string{"auto", "the"}})
+	assert.ErrorIs(t, err, ErrBadArguments)
+	err = service.AddSynonyms(ctx, SynonymGroup{Words: []string{"dog", "dogs"}})
+	assert.ErrorIs(t, err, ErrBadArguments)
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -15,6 +16,8 @@ import (
 	"yadro.com/course/search/adapters/db"
 	searchgrpc "yadro.com/course/search/adapters/grpc"
 	index_initiator "yadro.com/course/search/adapters/index-initiator"
+	"yadro.com/course/search/adapters/snapshot"
+	"yadro.com/course/search/adapters/synonyms"
 	"yadro.com/course/search/adapters/words"
 	"yadro.com/course/search/config"
 	"yadro.com/course/search/core"
@@ -53,12 +56,27 @@ func run(cfg config.Config, log *slog.Logger) error {
 	}
 
 	// service
-	searcher, err := core.NewService(log, storage, words)
+	boosts := core.FieldBoosts{
+		core.FieldTitle:      cfg.TitleBoost,
+		core.FieldAlt:        cfg.AltBoost,
+		core.FieldTranscript: cfg.TranscriptBoost,
+	}
+	searcher, err := core.NewService(
+		log, storage, words, snapshot.New(cfg.SnapshotPath), synonyms.New(cfg.SynonymsPath),
+		cfg.Backend, boosts, core.NewQueryCache(cfg.CacheSize, cfg.CacheTTL),
+	)
 	if err != nil {
 		return fmt.Errorf("failed create Update service: %v", err)
 	}
+	if err := searcher.LoadSynonyms(context.Background()); err != nil {
+		log.Warn("search goes without synonyms", "reason", err)
+	}
 
 	initiator := index_initiator.NewTickerInitiator(searcher, cfg.IndexTTL)
+	if err := searcher.LoadIndex(context.Background()); err != nil {
+		log.Warn("index is built from the database", "reason", err)
+		initiator.Reindex()
+	}
 
 	// grpc server
 	listener, err := net.Listen("tcp", cfg.Address)
@@ -67,7 +85,7 @@ func run(cfg config.Config, log *slog.Logger) error {
 	}
 
 	s := grpc.NewServer()
-	searchpb.RegisterSearchServer(s, searchgrpc.NewServer(searcher))
+	searchpb.RegisterSearchServer(s, searchgrpc.NewServer(searcher, initiator))
 	reflection.Register(s)
 
 	// context for Ctrl-C
@@ -77,6 +95,25 @@ func run(cfg config.Config, log *slog.Logger) error {
 	go initiator.Start(ctx, log)
 	defer initiator.Stop()
 
+	// SIGHUP reloads the synonyms file after it was edited by hand
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+	go func() {
+		for {
+			select {
+			case <-reload:
+				if err := searcher.LoadSynonyms(ctx); err != nil {
+					log.Error("failed to reload synonyms", "error", err)
+					continue
+				}
+				log.Info("synonyms reloaded")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	go func() {
 		<-ctx.Done()
 		log.Debug("shutting down server")
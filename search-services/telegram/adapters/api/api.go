@@ -29,10 +29,13 @@ func New(baseURL string, logger *slog.Logger) *APIClient {
 	}
 }
 
-func (c *APIClient) Search(ctx context.Context, limit int, words string) (core.SearchResult, error) {
+func (c *APIClient) Search(ctx context.Context, limit int, words, cursor string) (core.SearchResult, error) {
 	params := url.Values{}
 	params.Add("limit", strconv.Itoa(limit))
 	params.Add("phrase", words)
+	if cursor != "" {
+		params.Add("cursor", cursor)
+	}
 
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -62,6 +65,89 @@ func (c *APIClient) Search(ctx context.Context, limit int, words string) (core.S
 	return result, nil
 }
 
+func (c *APIClient) Similar(ctx context.Context, id, limit int) ([]core.Comics, error) {
+	params := url.Values{}
+	params.Add("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		fmt.Sprintf("%s/api/comics/%d/similar?%s", c.baseURL, id, params.Encode()),
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, core.ErrNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Comics []core.Comics `json:"comics"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response failed: %w", err)
+	}
+
+	return result.Comics, nil
+}
+
+func (c *APIClient) GetComic(ctx context.Context, id int) (core.ComicDetails, error) {
+	return c.getComic(ctx, fmt.Sprintf("%s/api/comics/%d", c.baseURL, id))
+}
+
+func (c *APIClient) Random(ctx context.Context, phrase string) (core.ComicDetails, error) {
+	u := c.baseURL + "/api/comics/random"
+	if phrase != "" {
+		params := url.Values{}
+		params.Add("phrase", phrase)
+		u += "?" + params.Encode()
+	}
+	return c.getComic(ctx, u)
+}
+
+func (c *APIClient) Latest(ctx context.Context) (core.ComicDetails, error) {
+	return c.getComic(ctx, c.baseURL+"/api/comics/latest")
+}
+
+func (c *APIClient) getComic(ctx context.Context, u string) (core.ComicDetails, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return core.ComicDetails{}, fmt.Errorf("create request failed: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return core.ComicDetails{}, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return core.ComicDetails{}, core.ErrNotFound
+	default:
+		return core.ComicDetails{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var comics core.ComicDetails
+	if err := json.NewDecoder(resp.Body).Decode(&comics); err != nil {
+		return core.ComicDetails{}, fmt.Errorf("decode response failed: %w", err)
+	}
+	return comics, nil
+}
+
 func (c *APIClient) Update(ctx context.Context) (err error) {
 	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/db/update", nil)
 	if err != nil {
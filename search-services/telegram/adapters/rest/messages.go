@@ -1,9 +1,16 @@
 package rest
 
-const msgHelp = `Я умею находить комиксы по интересующей теме:)`
+const msgHelp = `Я умею находить комиксы по интересующей теме:)
+
+/search запрос - поиск комиксов по ключевым словам
+/similar N - комиксы, похожие на комикс N
+/comic N - комикс N: заголовок, дата, ссылка и alt-текст
+/random [запрос] - случайный комикс, можно только из найденных по запросу
+/latest - последний комикс`
 
 const msgHello = "Привет! 👾\n\n" + msgHelp
 
 const (
 	msgUnknownCommand = "Непонятная команда 🤔"
+	msgMore           = "Ещё"
 )
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"log/slog"
 	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"yadro.com/course/telegram/core"
 )
@@ -26,7 +28,7 @@ func New(apiClient core.APIClient, tgClient core.TelegramClient, logger *slog.Lo
 	return &Handler{apiClient: apiClient, tgClint: tgClient, userStates: make(map[int64]*core.UserState), adminTokens: make(map[int64]string), log: logger}
 }
 
-func (h *Handler) HandleCommand(ctx context.Context, cmd string, chatID int64) error {
+func (h *Handler) HandleCommand(ctx context.Context, cmd, args string, chatID int64) error {
 	switch cmd {
 	case "/search":
 		h.stateMu.Lock()
@@ -34,6 +36,28 @@ func (h *Handler) HandleCommand(ctx context.Context, cmd string, chatID int64) e
 		h.stateMu.Unlock()
 
 		return h.tgClint.SendMessage(ctx, chatID, "Введите сколько комикосв вы бы хотели найти")
+	case "/similar":
+		if args != "" {
+			return h.sendSimilar(ctx, chatID, args)
+		}
+		h.stateMu.Lock()
+		h.userStates[chatID] = &core.UserState{Step: "similar"}
+		h.stateMu.Unlock()
+
+		return h.tgClint.SendMessage(ctx, chatID, "Введите номер комикса")
+	case "/comic":
+		if args != "" {
+			return h.sendComic(ctx, chatID, args)
+		}
+		h.stateMu.Lock()
+		h.userStates[chatID] = &core.UserState{Step: "comic"}
+		h.stateMu.Unlock()
+
+		return h.tgClint.SendMessage(ctx, chatID, "Введите номер комикса")
+	case "/random":
+		return h.sendRandom(ctx, chatID, strings.TrimSpace(args))
+	case "/latest":
+		return h.sendLatest(ctx, chatID)
 	case "/help":
 		return h.sendHelp(ctx, chatID)
 	case "/start":
@@ -116,13 +140,21 @@ func (h *Handler) HandleRegularMessage(ctx context.Context, text string, chatID
 		return h.tgClint.SendMessage(ctx, chatID, "Введите на какую тему вы бы хотели найти комиксы")
 	case "phrase":
 		state.Phrase = text
-		defer delete(h.userStates, chatID)
-
-		results, err := h.apiClient.Search(ctx, state.Limit, state.Phrase)
-		if err != nil {
-			return fmt.Errorf("search failed: %w", err)
+		state.Cursor, state.Shown = "", 0
+		return h.searchPage(ctx, chatID, state)
+	case "more":
+		if text != msgMore {
+			// anything else is a new phrase searched with the same limit
+			state.Phrase = text
+			state.Cursor, state.Shown = "", 0
 		}
-		return h.sendComicsResults(ctx, chatID, results)
+		return h.searchPage(ctx, chatID, state)
+	case "similar":
+		delete(h.userStates, chatID)
+		return h.sendSimilar(ctx, chatID, text)
+	case "comic":
+		delete(h.userStates, chatID)
+		return h.sendComic(ctx, chatID, text)
 	case "login":
 		state.User = text
 		state.Step = "password"
@@ -147,6 +179,128 @@ func (h *Handler) HandleRegularMessage(ctx context.Context, text string, chatID
 	}
 }
 
+// searchPage sends the next page of the search results. The state is kept
+// while there are more pages or a suggestion to press.
+func (h *Handler) searchPage(ctx context.Context, chatID int64, state *core.UserState) error {
+	results, err := h.apiClient.Search(ctx, state.Limit, state.Phrase, state.Cursor)
+	if err != nil {
+		delete(h.userStates, chatID)
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if results.Total == 0 && results.Suggestion != "" {
+		// pressing the suggestion repeats the search
+		state.Step = "phrase"
+		return h.tgClint.SendKeyboard(ctx, chatID, formatResultsHTML(results, state.Shown), []string{results.Suggestion})
+	}
+	if results.NextCursor != "" {
+		msg := formatResultsHTML(results, state.Shown)
+		state.Step = "more"
+		state.Cursor = results.NextCursor
+		state.Shown += len(results.Comics)
+		return h.tgClint.SendKeyboard(ctx, chatID, msg, []string{msgMore})
+	}
+	shown := state.Shown
+	delete(h.userStates, chatID)
+	return h.sendComicsResults(ctx, chatID, results, shown)
+}
+
+// similarLimit is how many similar comics the bot shows.
+const similarLimit = 5
+
+func (h *Handler) sendSimilar(ctx context.Context, chatID int64, text string) error {
+	id, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || id <= 0 {
+		return h.tgClint.SendMessage(ctx, chatID, "Введите номер комикса, число больше 0")
+	}
+	comics, err := h.apiClient.Similar(ctx, id, similarLimit)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return h.tgClint.SendMessage(ctx, chatID, fmt.Sprintf("Комикс #%d не найден", id))
+		}
+		return fmt.Errorf("similar failed: %w", err)
+	}
+	if len(comics) == 0 {
+		return h.tgClint.SendMessage(ctx, chatID, "Похожих комиксов не найдено")
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Похожие на #%d:\n\n", id))
+	writeComics(&builder, comics, 0)
+	return h.tgClint.SendMessage(ctx, chatID, builder.String())
+}
+
+func (h *Handler) sendComic(ctx context.Context, chatID int64, text string) error {
+	id, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || id <= 0 {
+		return h.tgClint.SendMessage(ctx, chatID, "Введите номер комикса, число больше 0")
+	}
+	comics, err := h.apiClient.GetComic(ctx, id)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return h.tgClint.SendMessage(ctx, chatID, fmt.Sprintf("Комикс #%d не найден", id))
+		}
+		return fmt.Errorf("get comic failed: %w", err)
+	}
+	return h.tgClint.SendMessage(ctx, chatID, formatComicHTML(comics))
+}
+
+func (h *Handler) sendRandom(ctx context.Context, chatID int64, phrase string) error {
+	comics, err := h.apiClient.Random(ctx, phrase)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			if phrase == "" {
+				return h.tgClint.SendMessage(ctx, chatID, "Комиксов пока нет")
+			}
+			return h.tgClint.SendMessage(ctx, chatID, fmt.Sprintf("По запросу «%s» ничего не найдено", html.EscapeString(phrase)))
+		}
+		return fmt.Errorf("get random comic failed: %w", err)
+	}
+	return h.sendComicPhoto(ctx, chatID, comics)
+}
+
+func (h *Handler) sendLatest(ctx context.Context, chatID int64) error {
+	comics, err := h.apiClient.Latest(ctx)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return h.tgClint.SendMessage(ctx, chatID, "Комиксов пока нет")
+		}
+		return fmt.Errorf("get latest comic failed: %w", err)
+	}
+	return h.sendComicPhoto(ctx, chatID, comics)
+}
+
+// captionLimit is the longest photo caption Telegram accepts.
+const captionLimit = 1024
+
+// sendComicPhoto sends the comic image with its title and alt text,
+// the alt text is left out if the caption gets too long.
+func (h *Handler) sendComicPhoto(ctx context.Context, chatID int64, comics core.ComicDetails) error {
+	caption := fmt.Sprintf("<b>#%d %s</b>", comics.ID, html.EscapeString(comics.Title))
+	if comics.Published != "" {
+		caption += fmt.Sprintf("\nОпубликован: %s", html.EscapeString(comics.Published))
+	}
+	if comics.Alt != "" {
+		withAlt := caption + fmt.Sprintf("\n\n<i>%s</i>", html.EscapeString(comics.Alt))
+		if utf8.RuneCountInString(withAlt) <= captionLimit {
+			caption = withAlt
+		}
+	}
+	return h.tgClint.SendPhoto(ctx, chatID, comics.URL, caption)
+}
+
+func formatComicHTML(comics core.ComicDetails) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("<b>#%d %s</b>\n", comics.ID, html.EscapeString(comics.Title)))
+	if comics.Published != "" {
+		builder.WriteString(fmt.Sprintf("Опубликован: %s\n", html.EscapeString(comics.Published)))
+	}
+	builder.WriteString(html.EscapeString(comics.URL) + "\n")
+	if comics.Alt != "" {
+		builder.WriteString(fmt.Sprintf("\n<i>%s</i>\n", html.EscapeString(comics.Alt)))
+	}
+	return builder.String()
+}
+
 func (h *Handler) sendHelp(ctx context.Context, chatID int64) error {
 	return h.tgClint.SendMessage(ctx, chatID, msgHelp)
 }
@@ -171,27 +325,39 @@ func (h *Handler) sendStatsResult(ctx context.Context, chatId int64, result core
 
 	return h.tgClint.SendMessage(ctx, chatId, msg)
 }
-func formatResultsHTML(results core.SearchResult) string {
+
+// formatResultsHTML numbers comics after the shown ones of previous pages.
+func formatResultsHTML(results core.SearchResult, shown int) string {
 	var builder strings.Builder
 	if results.Total == 0 {
+		if results.Suggestion != "" {
+			return "Ничего не найдено. Возможно, вы имели в виду: " + html.EscapeString(results.Suggestion)
+		}
 		return "Ничего не найдено"
 	}
 
-	builder.WriteString("Результаты поиска:\n\n")
+	builder.WriteString(fmt.Sprintf("Результаты поиска (найдено %d):\n\n", results.Total))
 
-	for i, item := range results.Comics {
+	writeComics(&builder, results.Comics, shown)
+	return builder.String()
+}
+func writeComics(builder *strings.Builder, comics []core.Comics, shown int) {
+	for i, item := range comics {
 		builder.WriteString(fmt.Sprintf("%d. %s #%d\n",
-			i+1, item.URL, item.ID))
+			shown+i+1, html.EscapeString(item.URL), item.ID))
+		// snippets come as HTML with found words in <b>
+		if item.Snippet != "" {
+			builder.WriteString(fmt.Sprintf("<i>%s</i>\n", item.Snippet))
+		}
 	}
-	return builder.String()
 }
 func (h *Handler) sendLoginResults(ctx context.Context, chatId int64, token string) error {
 	h.log.Info("SetAdminToken")
 	h.SetAdminToken(chatId, token)
 	return h.tgClint.SendMessage(ctx, chatId, "У вас теперь есть права админа")
 }
-func (h *Handler) sendComicsResults(ctx context.Context, chatId int64, result core.SearchResult) error {
-	msg := formatResultsHTML(result)
+func (h *Handler) sendComicsResults(ctx context.Context, chatId int64, result core.SearchResult, shown int) error {
+	msg := formatResultsHTML(result, shown)
 	return h.tgClint.SendMessage(ctx, chatId, msg)
 }
 
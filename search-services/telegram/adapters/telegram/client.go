@@ -28,15 +28,60 @@ func NewBotClient(token string) *BotClient {
 	}
 }
 
+// Messages are sent as HTML, so text has to be escaped.
 func (b *BotClient) SendMessage(ctx context.Context, chatID int64, text string) error {
 	params := url.Values{}
 	params.Add("chat_id", strconv.FormatInt(chatID, 10))
 	params.Add("text", text)
+	params.Add("parse_mode", "HTML")
 
 	_, err := b.doRequest(ctx, "sendMessage", params)
 	return err
 }
 
+// SendKeyboard sends a message with one-time reply buttons,
+// pressing a button sends its text back as a regular message.
+func (b *BotClient) SendKeyboard(ctx context.Context, chatID int64, text string, buttons []string) error {
+	type button struct {
+		Text string `json:"text"`
+	}
+	keyboard := struct {
+		Keyboard        [][]button `json:"keyboard"`
+		OneTimeKeyboard bool       `json:"one_time_keyboard"`
+		ResizeKeyboard  bool       `json:"resize_keyboard"`
+	}{OneTimeKeyboard: true, ResizeKeyboard: true}
+	for _, label := range buttons {
+		keyboard.Keyboard = append(keyboard.Keyboard, []button{{Text: label}})
+	}
+
+	markup, err := json.Marshal(keyboard)
+	if err != nil {
+		return fmt.Errorf("encode keyboard failed: %w", err)
+	}
+
+	params := url.Values{}
+	params.Add("chat_id", strconv.FormatInt(chatID, 10))
+	params.Add("text", text)
+	params.Add("parse_mode", "HTML")
+	params.Add("reply_markup", string(markup))
+
+	_, err = b.doRequest(ctx, "sendMessage", params)
+	return err
+}
+
+// SendPhoto sends the image by its URL, Telegram downloads it itself.
+// The caption is HTML like messages.
+func (b *BotClient) SendPhoto(ctx context.Context, chatID int64, photoURL, caption string) error {
+	params := url.Values{}
+	params.Add("chat_id", strconv.FormatInt(chatID, 10))
+	params.Add("photo", photoURL)
+	params.Add("caption", caption)
+	params.Add("parse_mode", "HTML")
+
+	_, err := b.doRequest(ctx, "sendPhoto", params)
+	return err
+}
+
 func (b *BotClient) GetUpdatesChan() <-chan core.TelegramUpdate {
 	updates := make(chan core.TelegramUpdate, 100)
 
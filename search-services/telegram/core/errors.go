@@ -4,3 +4,4 @@ import "errors"
 
 var ErrAlreadyExists = errors.New("resource or task already exists")
 var ErrUnauthorized = errors.New("authentication failed")
+var ErrNotFound = errors.New("resource is not found")
@@ -1,11 +1,27 @@
 package core
 
+type Comics struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet"`
+}
+
+// ComicDetails is a comic with its texts, Published is a date like
+// 2006-01-02 or empty.
+type ComicDetails struct {
+	ID         int    `json:"id"`
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	Alt        string `json:"alt"`
+	Transcript string `json:"transcript"`
+	Published  string `json:"published"`
+}
+
 type SearchResult struct {
-	Comics []struct {
-		ID  int    `json:"id"`
-		URL string `json:"url"`
-	} `json:"comics"`
-	Total int `json:"total"`
+	Comics     []Comics `json:"comics"`
+	Total      int      `json:"total"`
+	Suggestion string   `json:"suggestion"`
+	NextCursor string   `json:"next_cursor"`
 }
 
 type TelegramUpdate struct {
@@ -34,6 +50,10 @@ type UserState struct {
 	Password string
 	Limit    int
 	Phrase   string
+	// Cursor requests the next page of the search, Shown counts comics
+	// sent on the previous pages.
+	Cursor string
+	Shown  int
 }
 
 type TokenVerifier string
@@ -3,7 +3,11 @@ package core
 import "context"
 
 type APIClient interface {
-	Search(ctx context.Context, limit int, words string) (SearchResult, error)
+	Search(ctx context.Context, limit int, words, cursor string) (SearchResult, error)
+	Similar(ctx context.Context, id, limit int) ([]Comics, error)
+	GetComic(ctx context.Context, id int) (ComicDetails, error)
+	Random(ctx context.Context, phrase string) (ComicDetails, error)
+	Latest(ctx context.Context) (ComicDetails, error)
 	Login(ctx context.Context, user, password string) (string, error)
 	UpdateComics(ctx context.Context, token string) error
 	Drop(ctx context.Context, token string) error
@@ -12,5 +16,7 @@ type APIClient interface {
 
 type TelegramClient interface {
 	SendMessage(ctx context.Context, chatID int64, text string) error
+	SendKeyboard(ctx context.Context, chatID int64, text string, buttons []string) error
+	SendPhoto(ctx context.Context, chatID int64, photoURL, caption string) error
 	GetUpdatesChan() <-chan TelegramUpdate
 }
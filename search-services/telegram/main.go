@@ -59,8 +59,8 @@ func main() {
 			}
 
 			if strings.HasPrefix(update.Message.Text, "/") {
-				cmd, _ := parseCommand(update.Message.Text)
-				if err := handler.HandleCommand(ctx, cmd, update.Message.Chat.ID); err != nil {
+				cmd, args := parseCommand(update.Message.Text)
+				if err := handler.HandleCommand(ctx, cmd, args, update.Message.Chat.ID); err != nil {
 					log.Error("Error handling command", "error", err)
 					_ = tgClient.SendMessage(
 						context.Background(),
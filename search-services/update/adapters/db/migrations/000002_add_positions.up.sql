@@ -0,0 +1 @@
+ALTER TABLE comics ADD COLUMN positions INTEGER[];
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS comics_version ON comics;
+DROP FUNCTION IF EXISTS comics_bump_version();
+ALTER TABLE comics DROP COLUMN IF EXISTS version;
+DROP SEQUENCE IF EXISTS comics_version_seq;
@@ -0,0 +1,15 @@
+CREATE SEQUENCE IF NOT EXISTS comics_version_seq;
+
+ALTER TABLE comics ADD COLUMN version BIGINT NOT NULL DEFAULT nextval('comics_version_seq');
+
+CREATE INDEX comics_version_idx ON comics (version);
+
+CREATE OR REPLACE FUNCTION comics_bump_version() RETURNS trigger AS $$
+BEGIN
+    NEW.version := nextval('comics_version_seq');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comics_version BEFORE UPDATE ON comics
+    FOR EACH ROW EXECUTE FUNCTION comics_bump_version();
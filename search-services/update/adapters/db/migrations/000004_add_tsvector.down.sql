@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS comics_tsv_idx;
+DROP TRIGGER IF EXISTS comics_tsv ON comics;
+DROP FUNCTION IF EXISTS comics_update_tsv();
+ALTER TABLE comics DROP COLUMN IF EXISTS tsv;
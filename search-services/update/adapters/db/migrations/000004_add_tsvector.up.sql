@@ -0,0 +1,20 @@
+ALTER TABLE comics ADD COLUMN tsv tsvector NOT NULL DEFAULT ''::tsvector;
+
+-- words are normalized already, so lexemes are built from them as they
+-- are with their positions, tsvector positions start from 1
+CREATE OR REPLACE FUNCTION comics_update_tsv() RETURNS trigger AS $$
+BEGIN
+    NEW.tsv := COALESCE((
+        SELECT string_agg(format('%s:%s', quote_literal(w), COALESCE(NEW.positions[i], i - 1) + 1), ' ')
+        FROM unnest(NEW.words) WITH ORDINALITY AS t(w, i)
+    ), '')::tsvector;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comics_tsv BEFORE INSERT OR UPDATE OF words, positions ON comics
+    FOR EACH ROW EXECUTE FUNCTION comics_update_tsv();
+
+UPDATE comics SET words = words;
+
+CREATE INDEX comics_tsv_idx ON comics USING GIN (tsv);
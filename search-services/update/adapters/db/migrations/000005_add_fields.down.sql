@@ -0,0 +1,18 @@
+DROP TRIGGER IF EXISTS comics_tsv ON comics;
+
+CREATE OR REPLACE FUNCTION comics_update_tsv() RETURNS trigger AS $$
+BEGIN
+    NEW.tsv := COALESCE((
+        SELECT string_agg(format('%s:%s', quote_literal(w), COALESCE(NEW.positions[i], i - 1) + 1), ' ')
+        FROM unnest(NEW.words) WITH ORDINALITY AS t(w, i)
+    ), '')::tsvector;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comics_tsv BEFORE INSERT OR UPDATE OF words, positions ON comics
+    FOR EACH ROW EXECUTE FUNCTION comics_update_tsv();
+
+ALTER TABLE comics DROP COLUMN IF EXISTS fields;
+
+UPDATE comics SET words = words;
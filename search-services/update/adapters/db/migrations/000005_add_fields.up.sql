@@ -0,0 +1,21 @@
+-- field of every word: title, alt or transcript, comics fetched before
+-- have no fields
+ALTER TABLE comics ADD COLUMN fields TEXT[];
+
+-- title words get weight A and alt words get weight B, so that full-text
+-- ranking can boost them and queries can be limited to a field
+CREATE OR REPLACE FUNCTION comics_update_tsv() RETURNS trigger AS $$
+BEGIN
+    NEW.tsv := COALESCE((
+        SELECT string_agg(format('%s:%s%s', quote_literal(w), COALESCE(NEW.positions[i], i - 1) + 1,
+            CASE NEW.fields[i] WHEN 'title' THEN 'A' WHEN 'alt' THEN 'B' ELSE '' END), ' ')
+        FROM unnest(NEW.words) WITH ORDINALITY AS t(w, i)
+    ), '')::tsvector;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS comics_tsv ON comics;
+
+CREATE TRIGGER comics_tsv BEFORE INSERT OR UPDATE OF words, positions, fields ON comics
+    FOR EACH ROW EXECUTE FUNCTION comics_update_tsv();
@@ -0,0 +1,3 @@
+ALTER TABLE comics DROP COLUMN IF EXISTS transcript;
+ALTER TABLE comics DROP COLUMN IF EXISTS alt;
+ALTER TABLE comics DROP COLUMN IF EXISTS title;
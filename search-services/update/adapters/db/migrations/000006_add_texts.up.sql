@@ -0,0 +1,5 @@
+-- original texts of comic fields for result snippets, comics fetched
+-- before have none
+ALTER TABLE comics ADD COLUMN title TEXT;
+ALTER TABLE comics ADD COLUMN alt TEXT;
+ALTER TABLE comics ADD COLUMN transcript TEXT;
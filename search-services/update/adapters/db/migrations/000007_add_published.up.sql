@@ -0,0 +1,2 @@
+-- publication date of a comic, comics fetched before have none
+ALTER TABLE comics ADD COLUMN published DATE;
@@ -0,0 +1,11 @@
+-- position of the first word of the title, the alt text and the
+-- transcript, comics fetched before texts were stored have none
+ALTER TABLE comics ADD COLUMN field_starts INTEGER[];
+
+-- comics stored with texts have fields numbered 100 positions apart
+-- after the byte length of the fields before
+UPDATE comics SET field_starts = ARRAY[
+    0,
+    octet_length(COALESCE(title, '')) + 100,
+    octet_length(COALESCE(title, '')) + octet_length(COALESCE(alt, '')) + 200]
+WHERE title IS NOT NULL;
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"strings"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
@@ -32,14 +33,41 @@ func New(log *slog.Logger, address string) (*DB, error) {
 
 func (db *DB) Add(ctx context.Context, comics core.Comics) error {
 
-	query := `INSERT INTO comics (id,url,words)
-      VALUES ($1,$2,$3)`
+	words, positions, fields := tokenColumns(comics.Tokens)
 
-	_, err := db.conn.Exec(query, comics.ID, comics.URL, pq.Array(comics.Words))
+	var published *time.Time
+	if !comics.Published.IsZero() {
+		published = &comics.Published
+	}
+
+	starts := make([]int64, 0, len(comics.FieldStarts))
+	for _, start := range comics.FieldStarts {
+		starts = append(starts, int64(start))
+	}
+
+	query := `INSERT INTO comics (id,url,words,positions,fields,title,alt,transcript,published,field_starts)
+      VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`
+
+	_, err := db.conn.Exec(query, comics.ID, comics.URL, pq.Array(words), pq.Array(positions), pq.Array(fields),
+		comics.Title, comics.Alt, comics.Transcript, published, pq.Array(starts))
 
 	return err
 }
 
+// tokenColumns splits tokens into the words, positions and fields
+// arrays of a comics row, the i-th elements describe the i-th token.
+func tokenColumns(tokens []core.Token) ([]string, []int64, []string) {
+	words := make([]string, 0, len(tokens))
+	positions := make([]int64, 0, len(tokens))
+	fields := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, token.Word)
+		positions = append(positions, int64(token.Position))
+		fields = append(fields, token.Field)
+	}
+	return words, positions, fields
+}
+
 func (db *DB) Stats(ctx context.Context) (core.DBStats, error) {
 
 	var (
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/update/core"
+)
+
+func TestTokenColumnsRoundTrip(t *testing.T) {
+	tokens := []core.Token{
+		{Word: "bobbi", Position: 1, Field: "title"},
+		{Word: "tabl", Position: 2, Field: "title"},
+		{Word: "tabl", Position: 9, Field: "alt"},
+		{Word: "school", Position: 3, Field: "transcript"},
+	}
+	words, positions, fields := tokenColumns(tokens)
+
+	// the arrays go through the same encoding as in Add and back
+	var (
+		storedWords     pq.StringArray
+		storedPositions pq.Int64Array
+		storedFields    pq.StringArray
+	)
+	roundTrip(t, pq.Array(words), &storedWords)
+	roundTrip(t, pq.Array(positions), &storedPositions)
+	roundTrip(t, pq.Array(fields), &storedFields)
+
+	require.Len(t, storedWords, len(tokens))
+	require.Len(t, storedPositions, len(tokens))
+	require.Len(t, storedFields, len(tokens))
+	for i, token := range tokens {
+		assert.Equal(t, token, core.Token{
+			Word:     storedWords[i],
+			Position: int(storedPositions[i]),
+			Field:    storedFields[i],
+		})
+	}
+}
+
+// roundTrip encodes the value like the driver does on insert and scans
+// it back like a select does.
+func roundTrip(t *testing.T, value driver.Valuer, dest sql.Scanner) {
+	t.Helper()
+	encoded, err := value.Value()
+	require.NoError(t, err)
+	if s, ok := encoded.(string); ok {
+		encoded = []byte(s)
+	}
+	require.NoError(t, dest.Scan(encoded))
+}
@@ -0,0 +1,37 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	searchpb "yadro.com/course/proto/search"
+)
+
+type Client struct {
+	log    *slog.Logger
+	client searchpb.SearchClient
+}
+
+func NewClient(address string, log *slog.Logger) (*Client, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		client: searchpb.NewSearchClient(conn),
+		log:    log,
+	}, nil
+}
+
+func (c Client) Reindex(ctx context.Context) error {
+	_, err := c.client.Reindex(ctx, &emptypb.Empty{})
+	if err != nil {
+		c.log.Error("Failed to call Reindex method", "error", err)
+		return err
+	}
+	return nil
+}
@@ -55,16 +55,37 @@ func (c Client) Get(ctx context.Context, id int) (core.XKCDInfo, error) {
 		Alt        string `json:"alt"`
 		SafeTitle  string `json:"safe_title"`
 		Transcript string `json:"transcript"`
+		Year       string `json:"year"`
+		Month      string `json:"month"`
+		Day        string `json:"day"`
 	}
 
 	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return core.XKCDInfo{}, fmt.Errorf("json decode failed: %w", err)
 	}
 
+	title := result.Title
+	if result.SafeTitle != result.Title {
+		title += " " + result.SafeTitle
+	}
 	return core.XKCDInfo{ID: id,
-		URL:         result.Img,
-		Description: result.Alt + " " + result.Title + " " + result.SafeTitle + " " + result.Transcript}, nil
+		URL:        result.Img,
+		Title:      title,
+		Alt:        result.Alt,
+		Transcript: result.Transcript,
+		Published:  published(result.Year, result.Month, result.Day)}, nil
+
+}
 
+// published is the date of a comic, it is zero if xkcd gives none.
+func published(year, month, day string) time.Time {
+	y, errY := strconv.Atoi(year)
+	m, errM := strconv.Atoi(month)
+	d, errD := strconv.Atoi(day)
+	if errY != nil || errM != nil || errD != nil {
+		return time.Time{}
+	}
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
 }
 
 func (c Client) LastID(ctx context.Context) (int, error) {
@@ -15,11 +15,12 @@ type XKCD struct {
 }
 
 type Config struct {
-	LogLevel     string `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
-	Address      string `yaml:"update_address" env:"UPDATE_ADDRESS" env-default:"localhost:80"`
-	XKCD         XKCD   `yaml:"xkcd"`
-	DBAddress    string `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
-	WordsAddress string `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"localhost:81"`
+	LogLevel      string `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
+	Address       string `yaml:"update_address" env:"UPDATE_ADDRESS" env-default:"localhost:80"`
+	XKCD          XKCD   `yaml:"xkcd"`
+	DBAddress     string `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
+	WordsAddress  string `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"localhost:81"`
+	SearchAddress string `yaml:"search_address" env:"SEARCH_ADDRESS" env-default:"localhost:83"`
 }
 
 func MustLoad(configPath string) Config {
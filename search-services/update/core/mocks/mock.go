@@ -259,10 +259,10 @@ func (m *MockWords) EXPECT() *MockWordsMockRecorder {
 }
 
 // Norm mocks base method.
-func (m *MockWords) Norm(ctx context.Context, phrase string) ([]string, error) {
+func (m *MockWords) Norm(ctx context.Context, phrase string) ([]core.Token, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Norm", ctx, phrase)
-	ret0, _ := ret[0].([]string)
+	ret0, _ := ret[0].([]core.Token)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -272,3 +272,41 @@ func (mr *MockWordsMockRecorder) Norm(ctx, phrase any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Norm", reflect.TypeOf((*MockWords)(nil).Norm), ctx, phrase)
 }
+
+// MockIndexer is a mock of Indexer interface.
+type MockIndexer struct {
+	ctrl     *gomock.Controller
+	recorder *MockIndexerMockRecorder
+	isgomock struct{}
+}
+
+// MockIndexerMockRecorder is the mock recorder for MockIndexer.
+type MockIndexerMockRecorder struct {
+	mock *MockIndexer
+}
+
+// NewMockIndexer creates a new mock instance.
+func NewMockIndexer(ctrl *gomock.Controller) *MockIndexer {
+	mock := &MockIndexer{ctrl: ctrl}
+	mock.recorder = &MockIndexerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIndexer) EXPECT() *MockIndexerMockRecorder {
+	return m.recorder
+}
+
+// Reindex mocks base method.
+func (m *MockIndexer) Reindex(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reindex", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reindex indicates an expected call of Reindex.
+func (mr *MockIndexerMockRecorder) Reindex(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reindex", reflect.TypeOf((*MockIndexer)(nil).Reindex), ctx)
+}
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type ServiceStatus string
 
 const (
@@ -18,14 +20,39 @@ type ServiceStats struct {
 	ComicsTotal int
 }
 
+// Fields of a comic are normalized separately, so that search can tell
+// a word of the title from a word of the transcript.
+const (
+	FieldTitle      = "title"
+	FieldAlt        = "alt"
+	FieldTranscript = "transcript"
+)
+
+type Token struct {
+	Word     string
+	Position int
+	Field    string
+}
+
 type Comics struct {
-	ID    int
-	URL   string
-	Words []string
+	ID         int
+	URL        string
+	Title      string
+	Alt        string
+	Transcript string
+	// Published is zero when xkcd gives no date.
+	Published time.Time
+	Tokens    []Token
+	// FieldStarts are positions of the first words of the title, the alt
+	// text and the transcript.
+	FieldStarts []int
 }
 
 type XKCDInfo struct {
-	ID          int
-	URL         string
-	Description string
+	ID         int
+	URL        string
+	Title      string
+	Alt        string
+	Transcript string
+	Published  time.Time
 }
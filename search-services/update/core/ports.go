@@ -26,5 +26,9 @@ type XKCD interface {
 }
 
 type Words interface {
-	Norm(ctx context.Context, phrase string) ([]string, error)
+	Norm(ctx context.Context, phrase string) ([]Token, error)
+}
+
+type Indexer interface {
+	Reindex(ctx context.Context) error
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 )
 
@@ -12,13 +13,14 @@ type Service struct {
 	db          DB
 	xkcd        XKCD
 	words       Words
+	indexer     Indexer
 	concurrency int
 	updateMu    sync.RWMutex
 	isUpdating  bool
 }
 
 func NewService(
-	log *slog.Logger, db DB, xkcd XKCD, words Words, concurrency int,
+	log *slog.Logger, db DB, xkcd XKCD, words Words, indexer Indexer, concurrency int,
 ) (*Service, error) {
 	if concurrency < 1 {
 		return nil, fmt.Errorf("wrong concurrency specified: %d", concurrency)
@@ -28,6 +30,7 @@ func NewService(
 		db:          db,
 		xkcd:        xkcd,
 		words:       words,
+		indexer:     indexer,
 		concurrency: concurrency,
 	}, nil
 }
@@ -105,15 +108,20 @@ func (s *Service) Update(ctx context.Context) (err error) {
 
 			}
 
-			words, normErr := s.words.Norm(ctx, comicsInfo.Description)
-			if err != nil {
+			tokens, starts, normErr := s.normFields(ctx, comicsInfo)
+			if normErr != nil {
 				errChan <- fmt.Errorf("failed to normalize words for comic %d: %w", id, normErr)
 				return
 			}
 			comicsData := Comics{
-				ID:    comicsInfo.ID,
-				URL:   comicsInfo.URL,
-				Words: words}
+				ID:          comicsInfo.ID,
+				URL:         comicsInfo.URL,
+				Title:       comicsInfo.Title,
+				Alt:         comicsInfo.Alt,
+				Transcript:  comicsInfo.Transcript,
+				Published:   comicsInfo.Published,
+				Tokens:      tokens,
+				FieldStarts: starts}
 
 			output <- comicsData
 		}()
@@ -134,6 +142,9 @@ func (s *Service) Update(ctx context.Context) (err error) {
 		count++
 	}
 	s.log.Debug("added new comics", "count", count)
+	if count > 0 {
+		s.reindex(ctx)
+	}
 
 	select {
 	case err := <-firstErrChan:
@@ -143,6 +154,44 @@ func (s *Service) Update(ctx context.Context) (err error) {
 	}
 }
 
+// fieldGap separates positions of comic fields, so that phrases do not
+// match across the end of one field and the start of the next one.
+const fieldGap = 100
+
+// normFields normalizes every field of a comic on its own and returns
+// the position every field starts at. A field starts at the byte length
+// of the fields before it plus fieldGap each, a text has no more words
+// than bytes.
+func (s *Service) normFields(ctx context.Context, info XKCDInfo) ([]Token, []int, error) {
+	fields := []struct {
+		name, text string
+	}{
+		{FieldTitle, info.Title},
+		{FieldAlt, info.Alt},
+		{FieldTranscript, info.Transcript},
+	}
+
+	var tokens []Token
+	starts := make([]int, 0, len(fields))
+	offset := 0
+	for _, field := range fields {
+		starts = append(starts, offset)
+		if strings.TrimSpace(field.text) != "" {
+			normed, err := s.words.Norm(ctx, field.text)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, token := range normed {
+				token.Position += offset
+				token.Field = field.name
+				tokens = append(tokens, token)
+			}
+		}
+		offset += len(field.text) + fieldGap
+	}
+	return tokens, starts, nil
+}
+
 func (s *Service) Stats(ctx context.Context) (ServiceStats, error) {
 
 	dbStats, err := s.db.Stats(ctx)
@@ -178,5 +227,14 @@ func (s *Service) Drop(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("unable to remove comics from database:%d", err)
 	}
+	s.reindex(ctx)
 	return nil
 }
+
+// reindex tells the search service that comics changed, the search
+// refreshes its index by timer anyway, so failures are only logged.
+func (s *Service) reindex(ctx context.Context) {
+	if err := s.indexer.Reindex(ctx); err != nil {
+		s.log.Error("failed to request reindexing", "error", err)
+	}
+}
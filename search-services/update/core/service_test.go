@@ -0,0 +1,92 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"yadro.com/course/update/core"
+	mock_core "yadro.com/course/update/core/mocks"
+)
+
+type serviceMocks struct {
+	db      *mock_core.MockDB
+	xkcd    *mock_core.MockXKCD
+	words   *mock_core.MockWords
+	indexer *mock_core.MockIndexer
+}
+
+func TestServiceReindex(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m serviceMocks)
+		run          func(ctx context.Context, s *core.Service) error
+	}{
+		{
+			name: "Update with new comics",
+			mockBehavior: func(m serviceMocks) {
+				m.db.EXPECT().IDs(gomock.Any()).Return([]int{1}, nil)
+				m.xkcd.EXPECT().LastID(gomock.Any()).Return(2, nil)
+				m.xkcd.EXPECT().Get(gomock.Any(), 2).Return(core.XKCDInfo{ID: 2, Title: "Cat"}, nil)
+				m.words.EXPECT().Norm(gomock.Any(), "Cat").Return([]core.Token{{Word: "cat"}}, nil)
+				// fields start after the byte length of the fields before and a gap
+				m.db.EXPECT().Add(gomock.Any(), gomock.Cond(func(c core.Comics) bool {
+					return slices.Equal(c.FieldStarts, []int{0, 103, 203})
+				})).Return(nil)
+				m.indexer.EXPECT().Reindex(gomock.Any()).Return(nil)
+			},
+			run: func(ctx context.Context, s *core.Service) error { return s.Update(ctx) },
+		},
+		{
+			name: "Update with nothing new",
+			mockBehavior: func(m serviceMocks) {
+				m.db.EXPECT().IDs(gomock.Any()).Return([]int{1, 2}, nil)
+				m.xkcd.EXPECT().LastID(gomock.Any()).Return(2, nil)
+			},
+			run: func(ctx context.Context, s *core.Service) error { return s.Update(ctx) },
+		},
+		{
+			name: "Drop",
+			mockBehavior: func(m serviceMocks) {
+				m.db.EXPECT().Drop(gomock.Any()).Return(nil)
+				m.indexer.EXPECT().Reindex(gomock.Any()).Return(nil)
+			},
+			run: func(ctx context.Context, s *core.Service) error { return s.Drop(ctx) },
+		},
+		{
+			name: "Drop with search unavailable",
+			mockBehavior: func(m serviceMocks) {
+				m.db.EXPECT().Drop(gomock.Any()).Return(nil)
+				m.indexer.EXPECT().Reindex(gomock.Any()).Return(errors.New("connection refused"))
+			},
+			run: func(ctx context.Context, s *core.Service) error { return s.Drop(ctx) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := serviceMocks{
+				db:      mock_core.NewMockDB(ctrl),
+				xkcd:    mock_core.NewMockXKCD(ctrl),
+				words:   mock_core.NewMockWords(ctrl),
+				indexer: mock_core.NewMockIndexer(ctrl),
+			}
+			tt.mockBehavior(m)
+
+			service, err := core.NewService(slog.Default(), m.db, m.xkcd, m.words, m.indexer, 1)
+			require.NoError(t, err)
+
+			assert.NoError(t, tt.run(context.Background(), service))
+		})
+	}
+}
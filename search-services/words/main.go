@@ -46,19 +46,24 @@ func (s *server) Norm(_ context.Context, in *wordspb.WordsRequest) (*wordspb.Wor
 	})
 
 	stemmedWords := make(map[string]bool)
+	tokens := make([]*wordspb.Token, 0, len(words))
 
-	for _, word := range words {
+	// position is the index of the word in the phrase, stop words included,
+	// so distances between tokens match the original text
+	for position, word := range words {
 		w := strings.ToLower(word)
 		if !english.IsStopWord(w) {
 			stemmed := english.Stem(w, false)
 			if stemmed != "" {
 				stemmedWords[stemmed] = true
+				tokens = append(tokens, &wordspb.Token{Word: stemmed, Position: int32(position)})
 			}
 		}
 	}
 
 	return &wordspb.WordsReply{
-		Words: slices.Collect(maps.Keys(stemmedWords)),
+		Words:  slices.Collect(maps.Keys(stemmedWords)),
+		Tokens: tokens,
 	}, nil
 }
 
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	wordspb "yadro.com/course/proto/words"
+)
+
+func TestNormTokens(t *testing.T) {
+	reply, err := (&server{}).Norm(context.Background(), &wordspb.WordsRequest{
+		Phrase: "The cats saw the cat, and a dog saw cats",
+	})
+	require.NoError(t, err)
+
+	type token struct {
+		word     string
+		position int32
+	}
+	var tokens []token
+	for _, tok := range reply.GetTokens() {
+		tokens = append(tokens, token{tok.GetWord(), tok.GetPosition()})
+	}
+	// stop words are dropped but keep their positions, repeated words
+	// get a token for every occurrence
+	assert.Equal(t, []token{
+		{"cat", 1}, {"saw", 2}, {"cat", 4}, {"dog", 7}, {"saw", 8}, {"cat", 9},
+	}, tokens)
+	assert.ElementsMatch(t, []string{"cat", "saw", "dog"}, reply.GetWords())
+}
+
+func TestNormErrors(t *testing.T) {
+	_, err := (&server{}).Norm(context.Background(), &wordspb.WordsRequest{})
+	assert.Error(t, err)
+}
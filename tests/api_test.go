@@ -158,8 +158,9 @@ type Comics struct {
 }
 
 type ComicsReply struct {
-	Comics []Comics `json:"comics"`
-	Total  int      `json:"total"`
+	Comics     []Comics `json:"comics"`
+	Total      int      `json:"total"`
+	NextCursor string   `json:"next_cursor"`
 }
 
 func TestSearchNoPhrase(t *testing.T) {
@@ -206,7 +207,7 @@ func TestSearchLimit2(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode, "need OK status")
 	var comics ComicsReply
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&comics), "decode failed")
-	require.Equal(t, 2, comics.Total)
+	require.True(t, comics.Total > 2, "total counts all found comics")
 	require.Equal(t, 2, len(comics.Comics))
 }
 
@@ -218,10 +219,40 @@ func TestSearchLimitDefault(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode, "need OK status")
 	var comics ComicsReply
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&comics), "decode failed")
-	require.Equal(t, 10, comics.Total)
+	require.True(t, comics.Total > 10, "total counts all found comics")
 	require.Equal(t, 10, len(comics.Comics))
 }
 
+func searchPage(t *testing.T, params string) ComicsReply {
+	resp, err := client.Get(address + "/api/search?phrase=linux&limit=2&" + params)
+	require.NoError(t, err, "failed to search")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "need OK status")
+	var comics ComicsReply
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&comics), "decode failed")
+	return comics
+}
+
+func TestSearchPages(t *testing.T) {
+	update(t)
+	first := searchPage(t, "")
+	require.NotEmpty(t, first.NextCursor)
+
+	second := searchPage(t, "cursor="+url.QueryEscape(first.NextCursor))
+	require.Equal(t, first.Total, second.Total)
+	require.Equal(t, 2, len(second.Comics))
+	require.NotEqual(t, first.Comics[0].ID, second.Comics[0].ID)
+	require.NotEqual(t, first.Comics[1].ID, second.Comics[0].ID)
+
+	byOffset := searchPage(t, "offset=2")
+	require.Equal(t, second.Comics, byOffset.Comics)
+
+	resp, err := client.Get(address + "/api/search?phrase=linux&offset=2&cursor=" + url.QueryEscape(first.NextCursor))
+	require.NoError(t, err, "failed to search")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "offset and cursor together")
+}
+
 func TestSearchPhrases(t *testing.T) {
 	update(t)
 	testCases := []struct {